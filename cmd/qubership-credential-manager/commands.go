@@ -0,0 +1,358 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/informer"
+	"github.com/Netcracker/qubership-credential-manager/pkg/manager"
+	"github.com/Netcracker/qubership-credential-manager/pkg/password"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func runPrepare(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("prepare", "Create -old copies of the secrets and lock them. The command is run when no command is provided.")
+	flags.register(fs, true)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if !flags.requireSecrets(fs) {
+		return exitUsage
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+	if err = credManager.PrepareOldCreds(ctx, credManager.SecretRefs(flags.secretNames())); err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+func runCleanup(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("cleanup", "Delete hook Job and Pod objects with the hook name prefix in the namespace.")
+	flags.register(fs, false)
+	hookName := fs.String("hook-name", utils.GetHookName(), "Prefix of the hook objects, overrides HOOK_NAME environment variable")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	credManager, err := flags.newManager(manager.WithHookName(*hookName))
+	if err != nil {
+		return fail(err)
+	}
+	if err = credManager.ClearHooks(ctx); err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+func runStatus(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("status", "Show lock state, presence of -old copy, changed keys, data hash and owner references of the secrets.\n"+
+		"Secret values are never printed.")
+	flags.register(fs, true)
+	output := fs.String("output", "table", "Output format: table or json")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(fs.Output(), "unknown output format %q\n", *output)
+		fs.Usage()
+		return exitUsage
+	}
+	if !flags.requireSecrets(fs) {
+		return exitUsage
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+
+	statuses, statusErr := credManager.Status(ctx, credManager.SecretRefs(flags.secretNames()))
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(statuses)
+	} else {
+		err = printStatusTable(statuses)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if statusErr != nil {
+		return fail(statusErr)
+	}
+	return exitOK
+}
+
+func printStatusTable(statuses []manager.SecretStatus) error {
+	writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "SECRET\tLOCK\tHOLDER\tOLD COPY\tADDED\tREMOVED\tCHANGED\tHASH\tOWNERS\tOLD COPY OWNERS")
+	for _, status := range statuses {
+		if status.Error != "" {
+			fmt.Fprintf(writer, "%s\terror: %s\n", status.Secret, status.Error)
+			continue
+		}
+		holder := "-"
+		if status.Lock != nil && status.Lock.Holder != "" {
+			holder = status.Lock.Holder
+		}
+		oldCopy := "missing"
+		if status.OldCopyExists {
+			oldCopy = "present"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", status.Secret, status.LockState, holder, oldCopy,
+			formatList(status.AddedKeys), formatList(status.RemovedKeys), formatList(status.ChangedKeys),
+			status.DataHash, formatOwners(status.OwnerReferences), formatOwners(status.OldCopyOwnerReferences))
+	}
+	return writer.Flush()
+}
+
+func formatList(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
+
+func formatOwners(ownerRefs []metav1.OwnerReference) string {
+	owners := make([]string, 0, len(ownerRefs))
+	for _, ownerRef := range ownerRefs {
+		owners = append(owners, ownerRef.Kind+"/"+ownerRef.Name)
+	}
+	return formatList(owners)
+}
+
+func runUnlock(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("unlock", "Release locks of the secrets regardless of lock holder and expiration.")
+	flags.register(fs, true)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if !flags.requireSecrets(fs) {
+		return exitUsage
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+	if err = credManager.ForceUnlock(ctx, credManager.SecretRefs(flags.secretNames())); err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+func runDiff(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("diff", fmt.Sprintf("Show keys changed since the previous credentials were saved, values are never printed.\n"+
+		"Exit code is %d if any secret has changes.", exitChanged))
+	flags.register(fs, true)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if !flags.requireSecrets(fs) {
+		return exitUsage
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+
+	code := exitOK
+	for _, secretRef := range credManager.SecretRefs(flags.secretNames()) {
+		diff, err := credManager.DiffCreds(ctx, secretRef)
+		if err != nil {
+			code = fail(err)
+			continue
+		}
+		if !diff.HasChanges() {
+			fmt.Fprintf(stdout, "%s: no changes\n", secretRef)
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: added keys: %v, removed keys: %v, changed keys: %v\n", secretRef, diff.AddedKeys, diff.RemovedKeys, diff.ChangedKeys)
+		if code == exitOK {
+			code = exitChanged
+		}
+	}
+	return code
+}
+
+// fileValues is repeatable key=path flag, values of the keys are read from files.
+type fileValues map[string]string
+
+func (v fileValues) String() string {
+	return fmt.Sprint(map[string]string(v))
+}
+
+func (v fileValues) Set(value string) error {
+	key, path, found := strings.Cut(value, "=")
+	if !found || key == "" || path == "" {
+		return fmt.Errorf("value must be in key=path form")
+	}
+	v[key] = path
+	return nil
+}
+
+func runRotate(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("rotate", "Write new credentials into the secret, so the change is processed by informer and ActualizeCreds.\n"+
+		"Values are generated for --generate keys according to the password policy flags or read from files.")
+	flags.register(fs, false)
+	secretName := fs.String("secret", "", "Secret name in name or namespace/name form")
+	files := fileValues{}
+	fs.Var(files, "from-file", "New value of the key read from the file in key=path form, may be repeated")
+	generate := fs.String("generate", "", "Comma separated keys which values are generated")
+	policy := registerPolicyFlags(fs)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	generatedKeys := utils.SplitList(*generate)
+	if *secretName == "" || len(files)+len(generatedKeys) == 0 {
+		fmt.Fprintln(fs.Output(), "--secret and at least one --from-file or --generate flag are required")
+		fs.Usage()
+		return exitUsage
+	}
+	if err := policy.Validate(); len(generatedKeys) > 0 && err != nil {
+		fmt.Fprintf(fs.Output(), "invalid password policy: %v\n", err)
+		return exitUsage
+	}
+	values, err := password.GenerateValues(generatedKeys, *policy)
+	if err != nil {
+		return fail(err)
+	}
+	for key, path := range files {
+		if _, found := values[key]; found {
+			fmt.Fprintf(fs.Output(), "key %s is provided both with --from-file and --generate flags\n", key)
+			return exitUsage
+		}
+		if values[key], err = os.ReadFile(path); err != nil {
+			return fail(err)
+		}
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+	if err = credManager.SetCreds(ctx, credManager.SecretRef(*secretName), values); err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+// registerPolicyFlags registers flags of the generated passwords policy.
+func registerPolicyFlags(fs *flag.FlagSet) *password.Policy {
+	policy := password.DefaultPolicy
+	fs.IntVar(&policy.Length, "length", policy.Length, "Length of generated passwords")
+	fs.BoolVar(&policy.Lowercase, "lowercase", policy.Lowercase, "Use lowercase letters in generated passwords")
+	fs.BoolVar(&policy.Uppercase, "uppercase", policy.Uppercase, "Use uppercase letters in generated passwords")
+	fs.BoolVar(&policy.Digits, "digits", policy.Digits, "Use digits in generated passwords")
+	fs.BoolVar(&policy.Special, "special", policy.Special, "Use special characters "+password.SpecialChars+" in generated passwords")
+	fs.StringVar(&policy.Exclude, "exclude", policy.Exclude, "Characters which must not be used in generated passwords")
+	return &policy
+}
+
+func runSchedule(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("schedule", "Generate new credentials of the secrets which are older than their max age.\n"+
+		"Max age is read from "+manager.MaxAgeAnnotation+" annotation of the secret or --max-age flag.\n"+
+		"With --once the check is performed once, so the command may be run by CronJob.")
+	flags.register(fs, true)
+	once := fs.Bool("once", false, "Check credentials age once and exit")
+	interval := fs.Duration("interval", manager.DefaultScheduleInterval, "Interval of credentials age checks")
+	maxAge := fs.String("max-age", os.Getenv("CREDENTIALS_MAX_AGE"),
+		"Max age of the secrets without annotation, e.g. 90d, overrides CREDENTIALS_MAX_AGE environment variable")
+	policy := registerPolicyFlags(fs)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if !flags.requireSecrets(fs) {
+		return exitUsage
+	}
+	var opts []manager.SchedulerOption
+	if *maxAge != "" {
+		age, err := manager.ParseMaxAge(*maxAge)
+		if err != nil {
+			fmt.Fprintln(fs.Output(), err)
+			return exitUsage
+		}
+		opts = append(opts, manager.WithMaxAge(age))
+	}
+	if err := policy.Validate(); err != nil {
+		fmt.Fprintf(fs.Output(), "invalid password policy: %v\n", err)
+		return exitUsage
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+
+	opts = append(opts, manager.WithPasswordPolicy(*policy), manager.WithScheduleInterval(*interval))
+	scheduler := credManager.NewRotationScheduler(credManager.SecretRefs(flags.secretNames()), opts...)
+	if !*once {
+		_ = scheduler.Start(ctx)
+		return exitOK
+	}
+	rotated, err := scheduler.RotateExpired(ctx)
+	for _, secretRef := range rotated {
+		fmt.Fprintf(stdout, "%s: new credentials generated\n", secretRef)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+func runWatch(ctx context.Context, args []string) int {
+	var flags commonFlags
+	fs := newFlagSet("watch", "Watch the secrets and print credentials change events as JSON lines until interrupted.")
+	flags.register(fs, true)
+	lease := fs.String("lease", "", "Name of the Lease, if set events are printed only while the Lease is held")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if !flags.requireSecrets(fs) {
+		return exitUsage
+	}
+	credManager, err := flags.newManager()
+	if err != nil {
+		return fail(err)
+	}
+
+	encoder := json.NewEncoder(stdout)
+	handler := func(event informer.Event) error {
+		return encoder.Encode(event)
+	}
+	secretRefs := credManager.SecretRefs(flags.secretNames())
+	if *lease != "" {
+		err = credManager.RunWithLease(ctx, informer.LeaseConfig{Name: *lease}, secretRefs, handler)
+	} else {
+		err = credManager.WatchRunnable(secretRefs, handler).Start(ctx)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
@@ -0,0 +1,107 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/manager"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPrintStatusTable(t *testing.T) {
+	output := &bytes.Buffer{}
+	prevStdout := stdout
+	stdout = output
+	t.Cleanup(func() { stdout = prevStdout })
+
+	statuses := []manager.SecretStatus{
+		{
+			Secret:        types.NamespacedName{Namespace: testNamespace, Name: "db"},
+			LockState:     lock.StateLocked,
+			Lock:          &lock.Record{Holder: "hook"},
+			OldCopyExists: true,
+			SecretDiff:    utils.SecretDiff{AddedKeys: []string{"token"}, ChangedKeys: []string{"password", "user"}},
+			DataHash:      "hash",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "app"},
+			},
+		},
+		{Secret: types.NamespacedName{Namespace: testNamespace, Name: "missing"}, Error: "not found"},
+	}
+	if err := printStatusTable(statuses); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("header and a line per secret are expected, got:\n%s", output)
+	}
+	want := [][]string{
+		{"SECRET", "LOCK", "HOLDER", "OLD", "COPY", "ADDED", "REMOVED", "CHANGED", "HASH", "OWNERS", "OLD", "COPY", "OWNERS"},
+		{"test/db", "Locked", "hook", "present", "token", "-", "password,user", "hash", "Deployment/app", "-"},
+		{"test/missing", "error:", "not", "found"},
+	}
+	for i, line := range lines {
+		if got := strings.Fields(line); strings.Join(got, " ") != strings.Join(want[i], " ") {
+			t.Errorf("line %d = %q, want fields %v", i, line, want[i])
+		}
+	}
+}
+
+func TestRunStatusJSON(t *testing.T) {
+	_, output := useFakeClients(t, newTestSecret("db", map[string]string{"password": "new-secret"}),
+		newTestSecret("db-old", map[string]string{"password": "old-secret"}))
+	args := []string{"status", "--namespace", testNamespace, "--secrets", "db,missing", "--output", "json"}
+	if got := run(context.Background(), args); got != exitFailed {
+		t.Errorf("run() = %d, want %d for missing secret", got, exitFailed)
+	}
+
+	var statuses []manager.SecretStatus
+	if err := json.Unmarshal(output.Bytes(), &statuses); err != nil {
+		t.Fatalf("output is not JSON status: %v\n%s", err, output)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("status of every secret must be printed, got %d", len(statuses))
+	}
+	if statuses[0].LockState != lock.StateUnlocked || !statuses[0].OldCopyExists || strings.Join(statuses[0].ChangedKeys, ",") != "password" {
+		t.Errorf("unexpected status of db secret: %+v", statuses[0])
+	}
+	if statuses[1].Error == "" {
+		t.Errorf("status of missing secret must contain the error")
+	}
+	for _, value := range []string{"new-secret", "old-secret"} {
+		if strings.Contains(output.String(), value) {
+			t.Errorf("secret value %q must not be printed:\n%s", value, output)
+		}
+	}
+}
+
+func TestRunStatusTable(t *testing.T) {
+	_, output := useFakeClients(t, newTestSecret("db", map[string]string{"password": "new-secret"}))
+	if got := run(context.Background(), []string{"status", "--namespace", testNamespace, "--secrets", "db"}); got != exitOK {
+		t.Fatalf("run() = %d, want %d", got, exitOK)
+	}
+	if !strings.Contains(output.String(), "test/db") || !strings.Contains(output.String(), "missing") {
+		t.Errorf("unexpected table output:\n%s", output)
+	}
+}
@@ -15,10 +15,173 @@
 package main
 
 import (
-	"github.com/Netcracker/qubership-credential-manager/pkg/hook"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/manager"
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
 	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
 )
 
+// Exit codes of the commands.
+const (
+	exitOK = iota
+	exitFailed
+	exitUsage
+	exitChanged
+)
+
+// Clients and output of the commands, tests replace them with fakes.
+var (
+	newK8SClient           = utils.NewK8SClient
+	newClientSet           = utils.NewClientSet
+	stdout       io.Writer = os.Stdout
+)
+
+// command is subcommand of the binary.
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, args []string) int
+}
+
+var commands = []command{
+	{name: "prepare", description: "Create -old copies of the secrets and lock them, the default command", run: runPrepare},
+	{name: "cleanup", description: "Delete hook Job and Pod objects", run: runCleanup},
+	{name: "status", description: "Show lock state and changes of the secrets", run: runStatus},
+	{name: "unlock", description: "Release locks of the secrets regardless of holder and expiration", run: runUnlock},
+	{name: "diff", description: "Show keys changed since the previous credentials were saved", run: runDiff},
+	{name: "rotate", description: "Write new credentials into the secret", run: runRotate},
+	{name: "schedule", description: "Generate new credentials of the secrets older than max age", run: runSchedule},
+	{name: "watch", description: "Watch the secrets and print credentials change events", run: runWatch},
+}
+
 func main() {
-	hook.PrepareOldCreds(utils.GetSecretNames())
+	// Command output is written to stdout, so logs are written to stderr
+	utils.SetLogOutput(os.Stderr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string) int {
+	// Hook image runs the binary without arguments
+	if len(args) == 0 {
+		return runPrepare(ctx, args)
+	}
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(ctx, args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage()
+	return exitUsage
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: qubership-credential-manager <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'qubership-credential-manager <command> --help' for command flags.\n")
+	fmt.Fprintf(os.Stderr, "Exit codes: %d - success, %d - failure, %d - invalid usage, %d - credentials changes found by diff.\n",
+		exitOK, exitFailed, exitUsage, exitChanged)
+}
+
+// commonFlags are flags overriding environment variables, which are shared by commands.
+type commonFlags struct {
+	namespace string
+	secrets   string
+}
+
+func newFlagSet(name, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: qubership-credential-manager %s [flags]\n\n%s\n\nFlags:\n", name, description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (f *commonFlags) register(fs *flag.FlagSet, withSecrets bool) {
+	fs.StringVar(&f.namespace, "namespace", "",
+		"Default namespace of the secrets. By default namespace of the service account or NAMESPACE environment variable is used")
+	if withSecrets {
+		fs.StringVar(&f.secrets, "secrets", os.Getenv("SECRET_NAMES"),
+			"Comma separated secret names in name or namespace/name form, overrides SECRET_NAMES environment variable")
+	}
+}
+
+// parseFlags parses command flags, exit code is returned if the command must not be run.
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK, false
+	} else if err != nil {
+		return exitUsage, false
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+func (f *commonFlags) secretNames() []string {
+	return utils.SplitList(f.secrets)
+}
+
+// requireSecrets reports usage error if no secrets are provided.
+func (f *commonFlags) requireSecrets(fs *flag.FlagSet) bool {
+	if len(f.secretNames()) > 0 {
+		return true
+	}
+	fmt.Fprintln(fs.Output(), "no secrets are provided with --secrets flag or SECRET_NAMES environment variable")
+	fs.Usage()
+	return false
+}
+
+// newManager creates manager from the environment clients and the flags.
+// Events are created synchronously, so they are written before the command exits.
+func (f *commonFlags) newManager(opts ...manager.Option) (*manager.CredentialManager, error) {
+	// Configuration errors are returned instead of panics, so the command fails with exitFailed code
+	namespace := f.namespace
+	if namespace == "" {
+		var err error
+		if namespace, err = utils.LookupNamespace(); err != nil {
+			return nil, err
+		}
+	}
+	k8sClient, err := newK8SClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create kubernetes client: %w", err)
+	}
+	clientSet, err := newClientSet()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create kubernetes clientset: %w", err)
+	}
+	eventRecorder := recorder.NewSyncEventRecorder(clientSet, recorder.DefaultComponent)
+	opts = append([]manager.Option{manager.WithEventRecorder(eventRecorder)}, opts...)
+	return manager.NewCredentialManagerFromEnv(k8sClient, clientSet, namespace, opts...)
+}
+
+// fail prints error of the command and returns failure exit code.
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return exitFailed
 }
@@ -0,0 +1,151 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "test"
+
+func newTestSecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data:       make(map[string][]byte, len(data)),
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+// useFakeClients makes the commands use fake clients with the objects and returns the client and command output.
+func useFakeClients(t *testing.T, objects ...client.Object) (client.Client, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("SECRET_NAMES", "")
+	k8sClient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	output := &bytes.Buffer{}
+	prevK8SClient, prevClientSet, prevStdout := newK8SClient, newClientSet, stdout
+	newK8SClient = func() (client.Client, error) { return k8sClient, nil }
+	newClientSet = func() (kubernetes.Interface, error) { return k8sfake.NewClientset(), nil }
+	stdout = output
+	t.Cleanup(func() {
+		newK8SClient, newClientSet, stdout = prevK8SClient, prevClientSet, prevStdout
+	})
+	return k8sClient, output
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "help", args: []string{"help"}, want: exitOK},
+		{name: "command help", args: []string{"status", "--help"}, want: exitOK},
+		{name: "unknown command", args: []string{"restore"}, want: exitUsage},
+		{name: "unknown flag", args: []string{"diff", "--unknown"}, want: exitUsage},
+		{name: "unexpected argument", args: []string{"diff", "--secrets", "db", "extra"}, want: exitUsage},
+		{name: "no secrets", args: []string{"diff", "--namespace", testNamespace}, want: exitUsage},
+		{name: "unknown output format", args: []string{"status", "--secrets", "db", "--output", "yaml"}, want: exitUsage},
+		{name: "rotate without values", args: []string{"rotate", "--secret", "db"}, want: exitUsage},
+		{name: "invalid max age", args: []string{"schedule", "--secrets", "db", "--max-age", "soon"}, want: exitUsage},
+		{name: "missing secret", args: []string{"diff", "--namespace", testNamespace, "--secrets", "missing"}, want: exitFailed},
+		{name: "unlock", args: []string{"unlock", "--namespace", testNamespace, "--secrets", "db"}, want: exitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeClients(t, newTestSecret("db", map[string]string{"password": "secret"}))
+			if got := run(context.Background(), tt.args); got != tt.want {
+				t.Errorf("run(%v) = %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunClientError(t *testing.T) {
+	useFakeClients(t)
+	newK8SClient = func() (client.Client, error) { return nil, errors.New("no kubeconfig") }
+	if got := run(context.Background(), []string{"unlock", "--namespace", testNamespace, "--secrets", "db"}); got != exitFailed {
+		t.Errorf("run() = %d, want %d", got, exitFailed)
+	}
+}
+
+func TestRunPrepare(t *testing.T) {
+	k8sClient, _ := useFakeClients(t, newTestSecret("db", map[string]string{"password": "secret"}))
+	t.Setenv("SECRET_NAMES", "db")
+	// The hook image runs the binary without arguments and the namespace from the environment
+	t.Setenv("NAMESPACE", testNamespace)
+	if got := run(context.Background(), nil); got != exitOK {
+		t.Fatalf("run() = %d, want %d", got, exitOK)
+	}
+	oldSecret := &corev1.Secret{}
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "db-old"}, oldSecret); err != nil {
+		t.Fatalf("cannot get -old copy: %v", err)
+	}
+	if string(oldSecret.Data["password"]) != "secret" {
+		t.Errorf("-old copy password = %q, want %q", oldSecret.Data["password"], "secret")
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldData    map[string]string
+		want       int
+		wantOutput string
+	}{
+		{name: "no changes", oldData: map[string]string{"password": "secret"}, want: exitOK, wantOutput: "test/db: no changes"},
+		{name: "changed", oldData: map[string]string{"password": "previous"}, want: exitChanged, wantOutput: "changed keys: [password]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output := useFakeClients(t, newTestSecret("db", map[string]string{"password": "secret"}), newTestSecret("db-old", tt.oldData))
+			if got := run(context.Background(), []string{"diff", "--namespace", testNamespace, "--secrets", "db"}); got != tt.want {
+				t.Errorf("run() = %d, want %d", got, tt.want)
+			}
+			if !strings.Contains(output.String(), tt.wantOutput) {
+				t.Errorf("output %q does not contain %q", output.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestRunRotate(t *testing.T) {
+	k8sClient, _ := useFakeClients(t, newTestSecret("db", map[string]string{"password": "secret"}))
+	args := []string{"rotate", "--namespace", testNamespace, "--secret", "db", "--generate", "password", "--length", "20"}
+	if got := run(context.Background(), args); got != exitOK {
+		t.Fatalf("run() = %d, want %d", got, exitOK)
+	}
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "db"}, secret); err != nil {
+		t.Fatalf("cannot get secret: %v", err)
+	}
+	if password := string(secret.Data["password"]); password == "secret" || len(password) != 20 {
+		t.Errorf("password %q is not regenerated with length 20", password)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package controller integrates credentials secrets watching with controller-runtime controllers.
+//
+// Example of usage with controller builder:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//		For(&v1.Database{}).
+//		Watches(&corev1.Secret{},
+//			controller.EnqueueRequests(reconcile.Request{NamespacedName: dbName}),
+//			builder.WithPredicates(controller.CredsChangedPredicate(secretRefs, utils.DiffOptions{}))).
+//		Complete(r)
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/informer"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// CredsChangedPredicate passes only credentials changes of the provided secrets.
+// Create, delete and generic events are filtered out, updates of locked secrets and unlock updates are ignored,
+// the same way as informer does.
+func CredsChangedPredicate(secretRefs []types.NamespacedName, diffOptions utils.DiffOptions) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			newSecret, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			if !slices.Contains(secretRefs, client.ObjectKeyFromObject(newSecret)) {
+				return false
+			}
+			_, changed := informer.DetectChange(oldSecret, newSecret, diffOptions)
+			return changed
+		},
+	}
+}
+
+// EnqueueRequests enqueues the provided requests, e.g. custom resource of the operator, on each event.
+func EnqueueRequests(requests ...reconcile.Request) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return requests
+	})
+}
+
+// EnqueueRequestForOwner enqueues owner of ownerType from the secret owner references.
+func EnqueueRequestForOwner(scheme *runtime.Scheme, mapper meta.RESTMapper, ownerType client.Object) handler.EventHandler {
+	return handler.EnqueueRequestForOwner(scheme, mapper, ownerType, handler.OnlyControllerOwner())
+}
+
+// Source creates source of credentials changes of the provided secrets for Builder.WatchesRawSource.
+func Source(cache cache.Cache, eventHandler handler.EventHandler, secretRefs []types.NamespacedName, diffOptions utils.DiffOptions) source.Source {
+	return source.Kind[client.Object](cache, &corev1.Secret{}, eventHandler, CredsChangedPredicate(secretRefs, diffOptions))
+}
@@ -19,108 +19,53 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/Netcracker/qubership-credential-manager/pkg/manager"
 	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-)
-
-var (
-	logger    = utils.GetLogger()
-	k8sClient = utils.GetK8SClient()
-	namespace = utils.GetNamespace()
 )
 
+// PrepareOldCreds creates "-old" copies of the provided secrets and locks them.
+// It panics if any of the secrets fails and is intended to be used by the hook binary.
 func PrepareOldCreds(secrets []string) {
-	for _, secretName := range secrets {
-		oldSecretName := fmt.Sprintf("%s-old", secretName)
-		logger.Info(fmt.Sprintf("Creation of secret %s was started", oldSecretName))
-		ctx := context.Background()
-
-		newSecret := &corev1.Secret{}
-		err := k8sClient.Get(ctx, types.NamespacedName{
-			Name: secretName, Namespace: namespace,
-		}, newSecret)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				logger.Info(fmt.Sprintf("secret %s is not found, skipping...", secretName))
-				continue
-			}
-			logger.Info(fmt.Sprintf("cannot get %s secret", secretName))
-			panic(err)
-		}
-		if isSecretLocked(newSecret) {
-			logger.Info("Secret is locked, skip old secret update...")
-			continue
-		}
-
-		isSecretExist, err := IsSecretExist(oldSecretName)
-		if err != nil {
-			panic(err)
-		}
-		oldSecret := oldSecret(oldSecretName)
-		oldSecret.Data = newSecret.Data
-		oldSecret.Labels = newSecret.Labels
-		if !isSecretExist {
-			err = k8sClient.Create(ctx, oldSecret)
-			if err != nil {
-				logger.Info(fmt.Sprintf("cannot create %s secret", oldSecret.Name))
-				panic(err)
-			}
-		} else {
-			err = k8sClient.Update(ctx, oldSecret)
-			if err != nil {
-				logger.Info(fmt.Sprintf("cannot update %s secret", oldSecret.Name))
-				panic(err)
-			}
-		}
-
-		annotations := map[string]string{
-			utils.LockLabel: "true",
-		}
-		if newSecret.Annotations == nil {
-			newSecret.Annotations = annotations
-		} else {
-			for key, value := range annotations {
-				newSecret.Annotations[key] = value
-			}
-		}
-		err = k8sClient.Update(ctx, newSecret)
-		if err != nil {
-			logger.Info(fmt.Sprintf("cannot update %s secret", newSecret.Name))
-			panic(err)
-		}
+	if err := PrepareOldCredsContext(context.Background(), secrets); err != nil {
+		panic(err)
 	}
 }
 
-func isSecretLocked(secret *corev1.Secret) bool {
-	return secret.Annotations[utils.LockLabel] == "true"
+// PrepareOldCredsContext creates "-old" copies of the provided secrets and locks them.
+// Processing continues when a secret fails, the returned error aggregates all failures.
+// Secret names may be provided in "namespace/name" form, otherwise the current namespace is used.
+// Configuration errors, e.g. missing cluster config or invalid store kind, are returned as well.
+func PrepareOldCredsContext(ctx context.Context, secrets []string) error {
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+	return m.PrepareOldCreds(ctx, m.SecretRefs(secrets))
 }
 
 func IsSecretExist(name string) (bool, error) {
-	newSecret := &corev1.Secret{}
-	err := k8sClient.Get(context.Background(), types.NamespacedName{
-		Name: name, Namespace: namespace,
-	}, newSecret)
+	m, err := newManager()
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return false, nil
-		}
-		logger.Info(fmt.Sprintf("cannot get %s secret", name))
 		return false, err
 	}
-	return true, nil
+	return m.IsSecretExist(context.Background(), m.SecretRef(name))
 }
 
-func oldSecret(oldSecretName string) *corev1.Secret {
-	return &corev1.Secret{
-		Type: corev1.SecretTypeOpaque,
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      oldSecretName,
-			Namespace: namespace,
-		},
+// newManager creates manager from the environment clients returning configuration errors instead of panics.
+func newManager() (*manager.CredentialManager, error) {
+	namespace, err := utils.LookupNamespace()
+	if err != nil {
+		return nil, err
+	}
+	k8sClient, err := utils.NewK8SClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create kubernetes client: %w", err)
+	}
+	clientSet, err := utils.NewClientSet()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create kubernetes clientset: %w", err)
 	}
+	return manager.NewCredentialManagerFromEnv(k8sClient, clientSet, namespace)
 }
 
 func IsHook() bool {
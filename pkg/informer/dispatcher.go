@@ -0,0 +1,194 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	DefaultDebounce     = 1 * time.Second
+	DefaultWorkers      = 1
+	DefaultBaseDelay    = 1 * time.Second
+	DefaultMaxDelay     = 5 * time.Minute
+	DefaultMaxRetries   = 10
+	DefaultResyncPeriod = 1 * time.Hour
+)
+
+// RetriesExhaustedHandler is called when handler of the secret failed and maximum number of retries is reached.
+type RetriesExhaustedHandler func(event Event, err error)
+
+type dispatcherConfig struct {
+	debounce         time.Duration
+	workers          int
+	baseDelay        time.Duration
+	maxDelay         time.Duration
+	maxRetries       int
+	retriesExhausted RetriesExhaustedHandler
+}
+
+// dispatcher coalesces events of the secret until no events are received during debounce window and passes them to the handlers
+// from workqueue, so handler is never called concurrently for the same secret.
+// Failed handlers are retried with per-secret exponential backoff.
+type dispatcher struct {
+	config     dispatcherConfig
+	queue      workqueue.TypedRateLimitingInterface[types.NamespacedName]
+	handlerFor func(secretRef types.NamespacedName) RetryableEventHandler
+
+	pending map[types.NamespacedName]Event
+	// deadlines are times when pending events are processed, each event moves the deadline of its secret
+	deadlines map[types.NamespacedName]time.Time
+	inFlight  map[types.NamespacedName]chan struct{}
+	mutex     sync.Mutex
+	wg        sync.WaitGroup
+}
+
+func newDispatcher(config dispatcherConfig, handlerFor func(secretRef types.NamespacedName) RetryableEventHandler) *dispatcher {
+	d := &dispatcher{
+		config: config,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[types.NamespacedName](config.baseDelay, config.maxDelay),
+			workqueue.TypedRateLimitingQueueConfig[types.NamespacedName]{Name: "credential-manager"}),
+		handlerFor: handlerFor,
+		pending:    make(map[types.NamespacedName]Event),
+		deadlines:  make(map[types.NamespacedName]time.Time),
+		inFlight:   make(map[types.NamespacedName]chan struct{}),
+	}
+	workers := max(config.workers, 1)
+	d.wg.Add(workers)
+	for range workers {
+		go d.worker()
+	}
+	return d
+}
+
+// enqueue merges event with pending event of the secret and postpones its processing until debounce window passes without events.
+func (d *dispatcher) enqueue(event Event) {
+	d.mutex.Lock()
+	d.deadlines[event.Secret] = time.Now().Add(d.config.debounce)
+	d.mutex.Unlock()
+	d.addPending(event)
+	// Queue keeps the earliest time of the waiting item, so the item is re-added in process if the deadline was moved
+	d.queue.AddAfter(event.Secret, d.config.debounce)
+}
+
+func (d *dispatcher) addPending(event Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if pendingEvent, found := d.pending[event.Secret]; found {
+		event = mergeEvents(pendingEvent, event)
+	}
+	d.pending[event.Secret] = event
+}
+
+// requeue returns failed event to pending events, events received during the handler call are merged after it.
+func (d *dispatcher) requeue(event Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if pendingEvent, found := d.pending[event.Secret]; found {
+		event = mergeEvents(event, pendingEvent)
+	}
+	d.pending[event.Secret] = event
+}
+
+func (d *dispatcher) worker() {
+	defer d.wg.Done()
+	for {
+		secretRef, shutdown := d.queue.Get()
+		if shutdown {
+			return
+		}
+		d.process(secretRef)
+		d.queue.Done(secretRef)
+	}
+}
+
+func (d *dispatcher) process(secretRef types.NamespacedName) {
+	d.mutex.Lock()
+	if deadline, found := d.deadlines[secretRef]; found {
+		if wait := time.Until(deadline); wait > 0 {
+			d.mutex.Unlock()
+			d.queue.AddAfter(secretRef, wait)
+			return
+		}
+		delete(d.deadlines, secretRef)
+	}
+	event, found := d.pending[secretRef]
+	delete(d.pending, secretRef)
+	if !found {
+		d.mutex.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	d.inFlight[secretRef] = done
+	d.mutex.Unlock()
+	defer func() {
+		d.mutex.Lock()
+		delete(d.inFlight, secretRef)
+		d.mutex.Unlock()
+		close(done)
+	}()
+
+	handler := d.handlerFor(secretRef)
+	if handler == nil {
+		d.queue.Forget(secretRef)
+		return
+	}
+	err := handler(event)
+	if err == nil {
+		d.queue.Forget(secretRef)
+		return
+	}
+
+	reconcileFailures.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+	retries := d.queue.NumRequeues(secretRef)
+	if d.config.maxRetries >= 0 && retries >= d.config.maxRetries {
+		logger.Error(fmt.Sprintf("Reconcile of secret %s failed, retries are exhausted", secretRef), zap.Error(err))
+		d.queue.Forget(secretRef)
+		retriesExhausted.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+		if d.config.retriesExhausted != nil {
+			d.config.retriesExhausted(event, err)
+		}
+		return
+	}
+	logger.Error(fmt.Sprintf("Reconcile of secret %s failed, it will be retried", secretRef),
+		zap.Int("retry", retries+1), zap.Error(err))
+	d.requeue(event)
+	d.queue.AddRateLimited(secretRef)
+}
+
+// forget drops pending event of the secret and waits until its handler in progress is finished.
+func (d *dispatcher) forget(secretRef types.NamespacedName) {
+	d.mutex.Lock()
+	delete(d.pending, secretRef)
+	delete(d.deadlines, secretRef)
+	done := d.inFlight[secretRef]
+	d.mutex.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// stop drops pending events and waits until handlers in progress are finished.
+func (d *dispatcher) stop() {
+	d.queue.ShutDown()
+	d.wg.Wait()
+}
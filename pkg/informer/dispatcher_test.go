@@ -0,0 +1,237 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handlerCall is event received by the test handler and time of the call.
+type handlerCall struct {
+	event Event
+	at    time.Time
+}
+
+func newTestDispatcher(t *testing.T, config dispatcherConfig, handler RetryableEventHandler) *dispatcher {
+	d := newDispatcher(config, func(secretRef types.NamespacedName) RetryableEventHandler {
+		return handler
+	})
+	t.Cleanup(d.stop)
+	return d
+}
+
+func recordCalls(calls chan<- handlerCall, err error) RetryableEventHandler {
+	return func(event Event) error {
+		calls <- handlerCall{event: event, at: time.Now()}
+		return err
+	}
+}
+
+func waitCall(t *testing.T, calls <-chan handlerCall) handlerCall {
+	t.Helper()
+	select {
+	case call := <-calls:
+		return call
+	case <-time.After(eventTimeout):
+		t.Fatalf("handler is not called")
+		return handlerCall{}
+	}
+}
+
+func expectNoCall(t *testing.T, calls <-chan handlerCall, wait time.Duration) {
+	t.Helper()
+	select {
+	case call := <-calls:
+		t.Fatalf("unexpected handler call: %+v", call.event)
+	case <-time.After(wait):
+	}
+}
+
+func changedEvent(changedKeys ...string) Event {
+	return Event{Type: EventUpdated, Secret: testSecretRef, SecretDiff: utils.SecretDiff{ChangedKeys: changedKeys}}
+}
+
+func TestDispatcherDebounce(t *testing.T) {
+	calls := make(chan handlerCall, 10)
+	d := newTestDispatcher(t, dispatcherConfig{debounce: 100 * time.Millisecond, workers: 1}, recordCalls(calls, nil))
+
+	start := time.Now()
+	d.enqueue(changedEvent("password"))
+	d.enqueue(changedEvent("username"))
+	call := waitCall(t, calls)
+	if call.at.Sub(start) < 100*time.Millisecond {
+		t.Fatalf("handler is called before debounce window passed: %v", call.at.Sub(start))
+	}
+	if !reflect.DeepEqual(call.event.ChangedKeys, []string{"password", "username"}) {
+		t.Fatalf("events are not coalesced: %+v", call.event)
+	}
+	expectNoCall(t, calls, 200*time.Millisecond)
+}
+
+func TestDispatcherDebounceDeadlineReset(t *testing.T) {
+	calls := make(chan handlerCall, 10)
+	d := newTestDispatcher(t, dispatcherConfig{debounce: 300 * time.Millisecond, workers: 1}, recordCalls(calls, nil))
+
+	d.enqueue(changedEvent("password"))
+	time.Sleep(200 * time.Millisecond)
+	last := time.Now()
+	d.enqueue(changedEvent("username"))
+	call := waitCall(t, calls)
+	if call.at.Sub(last) < 300*time.Millisecond {
+		t.Fatalf("each event must postpone the handler by debounce window, called after %v", call.at.Sub(last))
+	}
+	if !reflect.DeepEqual(call.event.ChangedKeys, []string{"password", "username"}) {
+		t.Fatalf("events are not coalesced: %+v", call.event)
+	}
+	expectNoCall(t, calls, 400*time.Millisecond)
+}
+
+func TestDispatcherWithoutDebounce(t *testing.T) {
+	calls := make(chan handlerCall, 10)
+	d := newTestDispatcher(t, dispatcherConfig{workers: 1}, recordCalls(calls, nil))
+
+	start := time.Now()
+	d.enqueue(changedEvent("password"))
+	if call := waitCall(t, calls); call.at.Sub(start) > 100*time.Millisecond {
+		t.Fatalf("handler must be called right after the event, called after %v", call.at.Sub(start))
+	}
+}
+
+func TestDispatcherRetryMergesNewerEvents(t *testing.T) {
+	calls := make(chan handlerCall, 10)
+	errHandler := errors.New("database is not available")
+	var d *dispatcher
+	failed := false
+	d = newTestDispatcher(t, dispatcherConfig{workers: 1, baseDelay: 10 * time.Millisecond, maxDelay: time.Second, maxRetries: 5},
+		func(event Event) error {
+			calls <- handlerCall{event: event, at: time.Now()}
+			if failed {
+				return nil
+			}
+			failed = true
+			// Newer change is received while the handler is running
+			d.enqueue(Event{Type: EventUpdated, Secret: testSecretRef, SecretDiff: utils.SecretDiff{RemovedKeys: []string{"password"}},
+				OldResourceVersion: "2", NewResourceVersion: "3"})
+			return errHandler
+		})
+
+	d.enqueue(Event{Type: EventUpdated, Secret: testSecretRef, SecretDiff: utils.SecretDiff{ChangedKeys: []string{"password"}},
+		OldResourceVersion: "1", NewResourceVersion: "2"})
+	waitCall(t, calls)
+	retry := waitCall(t, calls).event
+	expected := utils.SecretDiff{RemovedKeys: []string{"password"}}
+	if !reflect.DeepEqual(retry.SecretDiff, expected) || retry.OldResourceVersion != "1" || retry.NewResourceVersion != "3" {
+		t.Fatalf("failed event must be merged before newer event, got %+v", retry)
+	}
+	expectNoCall(t, calls, 200*time.Millisecond)
+}
+
+func TestDispatcherRetryBackoff(t *testing.T) {
+	calls := make(chan handlerCall, 10)
+	attempts := 0
+	d := newTestDispatcher(t, dispatcherConfig{workers: 1, baseDelay: 100 * time.Millisecond, maxDelay: time.Second, maxRetries: 5},
+		func(event Event) error {
+			calls <- handlerCall{event: event, at: time.Now()}
+			attempts++
+			if attempts < 3 {
+				return errors.New("database is not available")
+			}
+			return nil
+		})
+
+	d.enqueue(changedEvent("password"))
+	first := waitCall(t, calls)
+	second := waitCall(t, calls)
+	third := waitCall(t, calls)
+	if delay := second.at.Sub(first.at); delay < 100*time.Millisecond {
+		t.Errorf("first retry must be delayed by base delay, delayed by %v", delay)
+	}
+	if delay := third.at.Sub(second.at); delay < 200*time.Millisecond {
+		t.Errorf("second retry must be delayed exponentially, delayed by %v", delay)
+	}
+	if !reflect.DeepEqual(third.event, first.event) {
+		t.Errorf("retried event differs from the failed one: %+v", third.event)
+	}
+	expectNoCall(t, calls, 500*time.Millisecond)
+}
+
+func TestDispatcherRetriesExhausted(t *testing.T) {
+	calls := make(chan handlerCall, 10)
+	errHandler := errors.New("database is not available")
+	exhausted := make(chan error, 1)
+	config := dispatcherConfig{workers: 1, baseDelay: 10 * time.Millisecond, maxDelay: 100 * time.Millisecond, maxRetries: 2,
+		retriesExhausted: func(event Event, err error) {
+			if event.Secret != testSecretRef {
+				t.Errorf("unexpected event of exhausted retries: %+v", event)
+			}
+			exhausted <- err
+		}}
+	d := newTestDispatcher(t, config, recordCalls(calls, errHandler))
+
+	d.enqueue(changedEvent("password"))
+	for range config.maxRetries + 1 {
+		waitCall(t, calls)
+	}
+	select {
+	case err := <-exhausted:
+		if !errors.Is(err, errHandler) {
+			t.Fatalf("unexpected error of exhausted retries: %v", err)
+		}
+	case <-time.After(eventTimeout):
+		t.Fatalf("retries exhausted handler is not called")
+	}
+	expectNoCall(t, calls, 300*time.Millisecond)
+}
+
+func TestMergeEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    Event
+		second   Event
+		expected Event
+	}{
+		{
+			name:     "updates of created secret",
+			first:    Event{Type: EventAdded, SecretDiff: utils.SecretDiff{AddedKeys: []string{"password"}}, NewResourceVersion: "1"},
+			second:   Event{Type: EventUpdated, SecretDiff: utils.SecretDiff{ChangedKeys: []string{"password"}}, OldResourceVersion: "1", NewResourceVersion: "2"},
+			expected: Event{Type: EventAdded, SecretDiff: utils.SecretDiff{AddedKeys: []string{"password"}}, NewResourceVersion: "2"},
+		},
+		{
+			name:     "key removed and added back",
+			first:    Event{Type: EventUpdated, SecretDiff: utils.SecretDiff{RemovedKeys: []string{"password"}}, OldResourceVersion: "1", NewResourceVersion: "2"},
+			second:   Event{Type: EventUpdated, SecretDiff: utils.SecretDiff{AddedKeys: []string{"password"}}, OldResourceVersion: "2", NewResourceVersion: "3"},
+			expected: Event{Type: EventUpdated, SecretDiff: utils.SecretDiff{ChangedKeys: []string{"password"}}, OldResourceVersion: "1", NewResourceVersion: "3"},
+		},
+		{
+			name:     "recreated secret",
+			first:    Event{Type: EventDeleted, SecretDiff: utils.SecretDiff{RemovedKeys: []string{"password", "username"}}, OldResourceVersion: "1"},
+			second:   Event{Type: EventAdded, SecretDiff: utils.SecretDiff{ChangedKeys: []string{"password"}}, NewResourceVersion: "5"},
+			expected: Event{Type: EventAdded, SecretDiff: utils.SecretDiff{ChangedKeys: []string{"password"}}, OldResourceVersion: "1", NewResourceVersion: "5"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if merged := mergeEvents(test.first, test.second); !reflect.DeepEqual(merged, test.expected) {
+				t.Errorf("unexpected merged event %+v, expected %+v", merged, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EventType is type of the watched credentials secret change.
+type EventType string
+
+const (
+	// EventUpdated is sent when credentials of the existing secret are changed
+	EventUpdated EventType = "Updated"
+	// EventAdded is sent when the secret is created or recreated after the watcher was started
+	EventAdded EventType = "Added"
+	// EventDeleted is sent when the secret is deleted
+	EventDeleted EventType = "Deleted"
+)
+
+// Event describes change of the watched credentials secret. Secret values are never included.
+type Event struct {
+	Type   EventType
+	Secret types.NamespacedName
+
+	// SecretDiff contains added, removed and changed keys between old and new secret versions
+	utils.SecretDiff
+
+	OldResourceVersion string
+	NewResourceVersion string
+
+	// LockState is lock state of the new secret version at the time of the event
+	LockState lock.State
+	// Lock is lock record of the new secret version, nil if secret is not locked
+	Lock *lock.Record
+}
+
+// EventHandler handles credentials secret change events.
+type EventHandler func(event Event)
+
+// RetryableEventHandler handles credentials secret change events, failed events are retried with backoff.
+type RetryableEventHandler func(event Event) error
+
+func newEvent(eventType EventType, oldSecret, newSecret *corev1.Secret, diff utils.SecretDiff, lockState lock.State) Event {
+	return Event{
+		Type:               eventType,
+		Secret:             types.NamespacedName{Namespace: newSecret.Namespace, Name: newSecret.Name},
+		SecretDiff:         diff,
+		OldResourceVersion: oldSecret.ResourceVersion,
+		NewResourceVersion: newSecret.ResourceVersion,
+		LockState:          lockState,
+		Lock:               lock.Get(newSecret),
+	}
+}
+
+// DetectChange checks if update of the secret is credentials change which requires reconcile.
+// Updates of locked secrets and unlock updates are not treated as credentials changes.
+func DetectChange(oldSecret, newSecret *corev1.Secret, diffOptions utils.DiffOptions) (Event, bool) {
+	now := time.Now()
+	lockState := lock.GetState(newSecret, now)
+	if lockState == lock.StateLocked {
+		logger.Info("Creds secret is locked by update job, skip password change procedure")
+		return Event{}, false
+	} else if lock.IsLocked(oldSecret, now) {
+		logger.Info("Creds secret just was unlocked, skip password change procedure")
+		return Event{}, false
+	}
+
+	diff := utils.DiffSecrets(oldSecret, newSecret, diffOptions)
+	if !diff.HasChanges() {
+		return Event{}, false
+	}
+	return newEvent(EventUpdated, oldSecret, newSecret, diff, lockState), true
+}
+
+// DetectRecreation checks if creation of the secret is credentials change which requires reconcile.
+// deletedSecret is the last known version of the deleted secret, all keys are treated as added if it is nil.
+func DetectRecreation(deletedSecret, newSecret *corev1.Secret, diffOptions utils.DiffOptions) (Event, bool) {
+	lockState := lock.GetState(newSecret, time.Now())
+	if lockState == lock.StateLocked {
+		logger.Info("Created creds secret is locked by update job, skip password change procedure")
+		return Event{}, false
+	}
+	if deletedSecret == nil {
+		deletedSecret = &corev1.Secret{}
+	}
+	diff := utils.DiffSecrets(deletedSecret, newSecret, diffOptions)
+	if !diff.HasChanges() {
+		return Event{}, false
+	}
+	return newEvent(EventAdded, deletedSecret, newSecret, diff, lockState), true
+}
+
+// newDeletedEvent creates event of the secret deletion, all keys of the deleted secret are reported as removed.
+func newDeletedEvent(secret *corev1.Secret, diffOptions utils.DiffOptions) Event {
+	diff := utils.DiffSecrets(secret, &corev1.Secret{}, diffOptions)
+	return Event{
+		Type:               EventDeleted,
+		Secret:             types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name},
+		SecretDiff:         diff,
+		OldResourceVersion: secret.ResourceVersion,
+		LockState:          lock.GetState(secret, time.Now()),
+		Lock:               lock.Get(secret),
+	}
+}
+
+// mergeEvents coalesces two sequential events of the same secret into one, first event must be the earlier one.
+func mergeEvents(first, second Event) Event {
+	merged := second
+	if first.Type == EventAdded && second.Type == EventUpdated {
+		// Updates of just created secret are part of the creation
+		merged.Type = EventAdded
+	}
+	merged.OldResourceVersion = first.OldResourceVersion
+	if first.Type == EventDeleted && second.Type == EventAdded {
+		// Keys of the recreated secret are already compared with the deleted version
+		return merged
+	}
+	merged.SecretDiff = utils.MergeDiffs(first.SecretDiff, second.SecretDiff)
+	return merged
+}
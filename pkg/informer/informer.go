@@ -16,181 +16,371 @@ package informer
 
 import (
 	"context"
-	"flag"
 	"fmt"
-	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
 	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
-	"go.uber.org/zap"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
-	logger    = utils.GetLogger()
-	namespace = utils.GetNamespace()
+	logger = utils.GetLogger()
 
-	activeWatchers = make(map[string]*Watcher)
-	mutex          = sync.Mutex{}
-	once           sync.Once
-	k8sClient      client.Client
+	defaultInformer *Informer
+	once            sync.Once
 )
 
-func GetK8SClient() client.Client {
-	once.Do(func() {
-		k8sClient = utils.GetK8SClient()
-	})
-	return k8sClient
+// Informer manages secret watchers for the provided clients.
+// Secrets may be located in any namespace, namespace is used for secrets provided by name only.
+// One shared informer per namespace is used for all watched secrets of the namespace.
+type Informer struct {
+	client    client.Client
+	clientSet kubernetes.Interface
+	namespace string
+
+	diffOptions   utils.DiffOptions
+	recorder      *recorder.Recorder
+	labelSelector string
+	resyncPeriod  time.Duration
+	dispatcher    dispatcherConfig
+
+	recreationPolicy RecreationPolicy
+	deletionPolicy   DeletionPolicy
+
+	activeWatchers     map[types.NamespacedName]*Watcher
+	namespaceInformers map[string]*namespaceInformer
+	activeDispatcher   *dispatcher
+	mutex              sync.Mutex
 }
 
-type Watcher struct {
-	secretName    string
-	informer      cache.SharedInformer
-	reconcileFunc func()
+// Option configures Informer.
+type Option func(i *Informer)
+
+// WithDiffOptions configures which parts of the secret are compared to detect credentials change.
+func WithDiffOptions(diffOptions utils.DiffOptions) Option {
+	return func(i *Informer) {
+		i.diffOptions = diffOptions
+	}
 }
 
-func (w Watcher) Start() {
-	// Prepare watcher clean
-	stopCh := make(chan struct{})
-	defer func() {
-		mutex.Lock()
-		close(stopCh)
-		delete(activeWatchers, w.secretName)
-		mutex.Unlock()
-	}()
+// WithRecorder enables Events posting on watched secrets.
+func WithRecorder(recorder *recorder.Recorder) Option {
+	return func(i *Informer) {
+		i.recorder = recorder
+	}
+}
 
-	//Start active watcher
-	logger.Info("Creds watcher started")
-	w.informer.Run(stopCh)
-	logger.Info("Creds watcher finished")
+// WithLabelSelector limits secrets cached by the shared informers with label selector.
+// Watched secrets must match the selector, otherwise their changes are not detected.
+func WithLabelSelector(labelSelector string) Option {
+	return func(i *Informer) {
+		i.labelSelector = labelSelector
+	}
 }
 
-func newWatcher(secretName string, reconcileFunc func()) (*Watcher, error) {
-	namespace := namespace
-	clientSet := getKubeClient()
-	if reconcileFunc == nil {
-		return nil, fmt.Errorf("no reconcile function was provided")
+// WithDebounce sets time window without changes of the secret after which coalesced changes are passed to one handler call.
+// By default DefaultDebounce is used.
+func WithDebounce(debounce time.Duration) Option {
+	return func(i *Informer) {
+		i.dispatcher.debounce = debounce
 	}
-	secretFields := map[string]string{"metadata.name": secretName}
-	informer := cache.NewSharedInformer(
-		&cache.ListWatch{
-			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
-				secretsList := &corev1.SecretList{}
-				listOps := &client.ListOptions{
-					FieldSelector: fields.SelectorFromSet(secretFields),
-					Namespace:     namespace,
-				}
-				err := GetK8SClient().List(context.Background(), secretsList, listOps)
-				return secretsList, err
-			},
-			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
-				return clientSet.CoreV1().Secrets(namespace).Watch(context.Background(), metav1.ListOptions{
-					FieldSelector: fields.SelectorFromSet(secretFields).String(),
-				})
-			},
-		},
-		&corev1.Secret{},
-		1*time.Hour, //TODO: check
-	)
+}
 
-	w := &Watcher{secretName: secretName, informer: informer, reconcileFunc: reconcileFunc}
+// WithWorkers sets number of handlers which may run concurrently for different secrets.
+// Handler is never called concurrently for the same secret. By default DefaultWorkers is used.
+func WithWorkers(workers int) Option {
+	return func(i *Informer) {
+		i.dispatcher.workers = workers
+	}
+}
 
-	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		UpdateFunc: w.credsUpdFunc,
-	})
-	if err != nil {
-		logger.Error("Cannot register credentials handler function", zap.Error(err))
-		return nil, err
+// WithBackoff sets per-secret exponential backoff of failed handlers retries.
+// By default DefaultBaseDelay and DefaultMaxDelay are used.
+func WithBackoff(baseDelay, maxDelay time.Duration) Option {
+	return func(i *Informer) {
+		i.dispatcher.baseDelay = baseDelay
+		i.dispatcher.maxDelay = maxDelay
 	}
+}
 
-	return w, nil
+// WithMaxRetries sets maximum number of failed handler retries, negative value means unlimited retries.
+// By default DefaultMaxRetries is used.
+func WithMaxRetries(maxRetries int) Option {
+	return func(i *Informer) {
+		i.dispatcher.maxRetries = maxRetries
+	}
 }
 
-func (w *Watcher) credsUpdFunc(oldObj, newObj interface{}) {
-	oldSecret, ok := oldObj.(*corev1.Secret)
-	if !ok {
-		errMsg := "old watched credentials secret is not Secret object"
-		logger.Error(errMsg)
-		return
+// WithRetriesExhaustedHandler sets function which is called when maximum number of failed handler retries is reached.
+func WithRetriesExhaustedHandler(handler RetriesExhaustedHandler) Option {
+	return func(i *Informer) {
+		i.dispatcher.retriesExhausted = handler
 	}
-	newSecret, ok := newObj.(*corev1.Secret)
-	if !ok {
-		errMsg := "new watched credentials secret is not Secret object"
-		logger.Error(errMsg)
-		return
+}
+
+// WithResyncPeriod sets resync period of the shared informers. By default DefaultResyncPeriod is used.
+func WithResyncPeriod(resyncPeriod time.Duration) Option {
+	return func(i *Informer) {
+		i.resyncPeriod = resyncPeriod
 	}
-	if locked := newSecret.Annotations[utils.LockLabel]; locked == "true" {
-		logger.Info("Creds secret is locked by update job, skip password change procedure")
-		return
-	} else if locked := oldSecret.Annotations[utils.LockLabel]; locked == "true" {
-		logger.Info("Creds secret just was unlocked, skip password change procedure")
-		return
+}
+
+// WithRecreationPolicy sets how creation of the watched secret is handled. By default RecreationIgnore is used.
+func WithRecreationPolicy(policy RecreationPolicy) Option {
+	return func(i *Informer) {
+		i.recreationPolicy = policy
 	}
+}
 
-	if utils.AreFieldsChanged(oldSecret, newSecret) {
-		logger.Info("New credentials found, starting reconcile...")
-		w.reconcileFunc()
+// WithDeletionPolicy sets how deletion of the watched secret is handled. By default DeletionIgnore is used.
+func WithDeletionPolicy(policy DeletionPolicy) Option {
+	return func(i *Informer) {
+		i.deletionPolicy = policy
 	}
 }
 
+// NewInformer creates Informer which works with provided clients, namespace is used as default one.
+func NewInformer(k8sClient client.Client, clientSet kubernetes.Interface, namespace string, opts ...Option) *Informer {
+	i := &Informer{
+		client:             k8sClient,
+		clientSet:          clientSet,
+		namespace:          namespace,
+		activeWatchers:     make(map[types.NamespacedName]*Watcher),
+		namespaceInformers: make(map[string]*namespaceInformer),
+		resyncPeriod:       DefaultResyncPeriod,
+		recreationPolicy:   RecreationIgnore,
+		deletionPolicy:     DeletionIgnore,
+		dispatcher: dispatcherConfig{
+			debounce:   DefaultDebounce,
+			workers:    DefaultWorkers,
+			baseDelay:  DefaultBaseDelay,
+			maxDelay:   DefaultMaxDelay,
+			maxRetries: DefaultMaxRetries,
+		},
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+func getDefaultInformer() *Informer {
+	once.Do(func() {
+		defaultInformer = NewInformer(utils.GetK8SClient(), utils.GetClientSet(), utils.GetNamespace(),
+			WithLabelSelector(utils.GetEnv("WATCH_LABEL_SELECTOR", "")),
+			WithDebounce(utils.GetEnvDuration("WATCH_DEBOUNCE", DefaultDebounce)),
+			WithResyncPeriod(utils.GetEnvDuration("WATCH_RESYNC_PERIOD", DefaultResyncPeriod)),
+			WithRecreationPolicy(RecreationPolicy(utils.GetEnv("WATCH_RECREATION_POLICY", string(RecreationIgnore)))),
+			WithDeletionPolicy(DeletionPolicy(utils.GetEnv("WATCH_DELETION_POLICY", string(DeletionIgnore)))))
+	})
+	return defaultInformer
+}
+
+func GetK8SClient() client.Client {
+	return getDefaultInformer().client
+}
+
 func Watch(secretNames []string, reconcileFunc func()) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-	for _, secretName := range secretNames {
-		// Init watcher
-		watcher := activeWatchers[secretName]
+	informer := getDefaultInformer()
+	return informer.Watch(utils.GetSecretRefs(secretNames, informer.namespace), reconcileFunc)
+}
 
-		if watcher == nil {
-			var err error
-			watcher, err = newWatcher(secretName, reconcileFunc)
-			if err != nil {
-				return err
-			}
-			activeWatchers[secretName] = watcher
-		} else {
-			logger.Info(fmt.Sprintf("Active watcher for secret %s already exist", secretName))
+// WatchEvents starts watchers for the provided secrets, handler receives event on each credentials change.
+func WatchEvents(secretNames []string, handler EventHandler) error {
+	informer := getDefaultInformer()
+	return informer.WatchEvents(utils.GetSecretRefs(secretNames, informer.namespace), handler)
+}
+
+// WatchContext starts watchers for the provided secrets which are stopped on context cancellation.
+func WatchContext(ctx context.Context, secretNames []string, reconcileFunc func()) (*WatchHandle, error) {
+	informer := getDefaultInformer()
+	return informer.WatchContext(ctx, utils.GetSecretRefs(secretNames, informer.namespace), reconcileFunc)
+}
+
+// WatchEventsContext starts watchers for the provided secrets which are stopped on context cancellation.
+func WatchEventsContext(ctx context.Context, secretNames []string, handler EventHandler) (*WatchHandle, error) {
+	informer := getDefaultInformer()
+	return informer.WatchEventsContext(ctx, utils.GetSecretRefs(secretNames, informer.namespace), handler)
+}
+
+// WatchRetryable starts watchers for the provided secrets which are stopped on context cancellation.
+// Failed handler calls are retried with per-secret exponential backoff.
+func WatchRetryable(ctx context.Context, secretNames []string, handler RetryableEventHandler) (*WatchHandle, error) {
+	informer := getDefaultInformer()
+	return informer.WatchRetryable(ctx, utils.GetSecretRefs(secretNames, informer.namespace), handler)
+}
+
+// Unwatch stops watchers of the provided secrets and waits until they are finished.
+func Unwatch(secretNames []string) {
+	informer := getDefaultInformer()
+	informer.Unwatch(utils.GetSecretRefs(secretNames, informer.namespace))
+}
+
+// Watch starts watchers for the provided secrets, reconcileFunc is triggered on credentials change.
+// reconcileFunc is called after debounce window, WithDebounce(0) calls it right after the change.
+func (i *Informer) Watch(secretRefs []types.NamespacedName, reconcileFunc func()) error {
+	_, err := i.WatchContext(context.Background(), secretRefs, reconcileFunc)
+	return err
+}
+
+// WatchEvents starts watchers for the provided secrets, handler receives event on each credentials change.
+func (i *Informer) WatchEvents(secretRefs []types.NamespacedName, handler EventHandler) error {
+	_, err := i.WatchEventsContext(context.Background(), secretRefs, handler)
+	return err
+}
+
+// WatchContext starts watchers for the provided secrets which are stopped on context cancellation.
+// Returned handle stops watchers started by this call, already active watchers are not included.
+// reconcileFunc is not triggered on secret deletion.
+func (i *Informer) WatchContext(ctx context.Context, secretRefs []types.NamespacedName, reconcileFunc func()) (*WatchHandle, error) {
+	if reconcileFunc == nil {
+		return nil, fmt.Errorf("no reconcile function was provided")
+	}
+	return i.WatchEventsContext(ctx, secretRefs, func(event Event) {
+		if event.Type != EventDeleted {
+			reconcileFunc()
+		}
+	})
+}
+
+// WatchEventsContext starts watchers for the provided secrets which are stopped on context cancellation.
+// Returned handle stops watchers started by this call, already active watchers are not included.
+func (i *Informer) WatchEventsContext(ctx context.Context, secretRefs []types.NamespacedName, handler EventHandler) (*WatchHandle, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("no reconcile function was provided")
+	}
+	return i.WatchRetryable(ctx, secretRefs, func(event Event) error {
+		handler(event)
+		return nil
+	})
+}
+
+// WatchRetryable starts watchers for the provided secrets which are stopped on context cancellation.
+// Failed handler calls are retried with per-secret exponential backoff until maximum number of retries is reached.
+// Returned handle stops watchers started by this call, already active watchers are not included.
+func (i *Informer) WatchRetryable(ctx context.Context, secretRefs []types.NamespacedName, handler RetryableEventHandler) (*WatchHandle, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("no reconcile function was provided")
+	}
+	for _, secretRef := range secretRefs {
+		if secretRef.Name == "" {
+			return nil, fmt.Errorf("secret name is not provided for namespace %q", secretRef.Namespace)
+		}
+	}
+	handle, err := i.startWatchers(ctx, secretRefs, handler)
+	if err != nil {
+		handle.Stop()
+		return nil, err
+	}
+	return handle, nil
+}
+
+func (i *Informer) startWatchers(ctx context.Context, secretRefs []types.NamespacedName, handler RetryableEventHandler) (*WatchHandle, error) {
+	// Secrets cached without data are requested before the mutex is held, so the first change is compared with full version
+	baselines := make(map[types.NamespacedName]*corev1.Secret)
+	for _, secretRef := range i.strippedSecrets(secretRefs) {
+		if secret := i.fetchSecret(ctx, secretRef); secret != nil {
+			baselines[secretRef] = secret
+		}
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	handle := &WatchHandle{}
+	for _, secretRef := range secretRefs {
+		// Init watcher
+		if i.activeWatchers[secretRef] != nil {
+			logger.Info(fmt.Sprintf("Active watcher for secret %s already exist", secretRef))
 			continue
 		}
-		go watcher.Start()
+		nsInformer, err := i.acquireNamespaceInformer(secretRef.Namespace)
+		if err != nil {
+			return handle, err
+		}
+		if i.activeDispatcher == nil {
+			i.activeDispatcher = newDispatcher(i.dispatcher, i.handlerFor)
+		}
+		watcher := newWatcher(ctx, i, secretRef, handler, i.activeDispatcher)
+		watcher.baseline = baselines[secretRef]
+		i.activeWatchers[secretRef] = watcher
+		watchedSecrets.Inc()
+		handle.watchers = append(handle.watchers, watcher)
+		go watcher.run(nsInformer)
 	}
 
-	return nil
+	return handle, nil
 }
 
-func getKubeClient() *kubernetes.Clientset {
-	k8sConfig, err := rest.InClusterConfig()
-	if err != nil {
-		var kubeconfig *string
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfig = flag.String("kubec", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-		} else {
-			kubeconfig = flag.String("kubec", "", "absolute path to the kubeconfig file")
+// Unwatch stops watchers of the provided secrets and waits until they are finished.
+func (i *Informer) Unwatch(secretRefs []types.NamespacedName) {
+	i.mutex.Lock()
+	watchers := make([]*Watcher, 0, len(secretRefs))
+	for _, secretRef := range secretRefs {
+		if watcher := i.activeWatchers[secretRef]; watcher != nil {
+			watchers = append(watchers, watcher)
 		}
-		flag.Parse()
+	}
+	i.mutex.Unlock()
 
-		// use the current context in kubeconfig
-		k8sConfig, err = clientcmd.BuildConfigFromFlags("", *kubeconfig)
-		if err != nil {
-			panic(err.Error())
+	for _, watcher := range watchers {
+		watcher.Stop()
+		logger.Info(fmt.Sprintf("Watcher for secret %s was stopped", watcher.secretRef))
+	}
+}
+
+func (i *Informer) handlerFor(secretRef types.NamespacedName) RetryableEventHandler {
+	watcher := i.getWatcher(secretRef)
+	if watcher == nil {
+		return nil
+	}
+	if i.deletionPolicy != DeletionCleanup {
+		return watcher.handler
+	}
+	return func(event Event) error {
+		if event.Type == EventDeleted {
+			if err := i.cleanupOldSecret(watcher.ctx, secretRef); err != nil {
+				return fmt.Errorf("cannot remove old copy of deleted secret %s: %w", secretRef, err)
+			}
 		}
+		return watcher.handler(event)
+	}
+}
 
+// getWatcher returns active watcher of the secret, nil is returned if the secret is not watched.
+func (i *Informer) getWatcher(secretRef types.NamespacedName) *Watcher {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.activeWatchers[secretRef]
+}
+
+// removeWatcher removes the watcher from active watchers and releases its namespace informer.
+// Pending event of the secret is dropped and handler in progress is awaited, so handlers must not stop their own watchers.
+func (i *Informer) removeWatcher(w *Watcher) {
+	i.mutex.Lock()
+	if i.activeWatchers[w.secretRef] != w {
+		i.mutex.Unlock()
+		return
 	}
-	k8sConfig.Timeout = 60 * time.Second
-	client, err := kubernetes.NewForConfig(k8sConfig)
-	if err != nil {
-		panic(err)
+	delete(i.activeWatchers, w.secretRef)
+	watchedSecrets.Dec()
+	nsInformer := i.releaseNamespaceInformer(w.secretRef.Namespace)
+	var dispatcher *dispatcher
+	if len(i.activeWatchers) == 0 {
+		dispatcher = i.activeDispatcher
+		i.activeDispatcher = nil
+	}
+	i.mutex.Unlock()
+	if nsInformer != nil {
+		nsInformer.stop()
+	}
+	if dispatcher != nil {
+		dispatcher.stop()
+	} else {
+		w.dispatcher.forget(w.secretRef)
 	}
-	return client
 }
@@ -0,0 +1,200 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test"
+	eventTimeout  = 5 * time.Second
+)
+
+var testSecretRef = types.NamespacedName{Namespace: testNamespace, Name: "db-credentials"}
+
+func newTestSecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data:       make(map[string][]byte, len(data)),
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+// testClientset is fake clientset which reports started watches, fake watch doesn't receive changes made before it is started.
+type testClientset struct {
+	*k8sfake.Clientset
+	watches chan struct{}
+}
+
+func newTestClientset(objects ...runtime.Object) *testClientset {
+	clientSet := &testClientset{Clientset: k8sfake.NewClientset(objects...), watches: make(chan struct{}, 100)}
+	clientSet.PrependWatchReactor("secrets", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		var opts metav1.ListOptions
+		if watchAction, ok := action.(k8stesting.WatchActionImpl); ok {
+			opts = watchAction.ListOptions
+		}
+		watcher, err := clientSet.Tracker().Watch(action.GetResource(), action.GetNamespace(), opts)
+		clientSet.watches <- struct{}{}
+		return true, watcher, err
+	})
+	return clientSet
+}
+
+func (c *testClientset) waitWatch(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.watches:
+	case <-time.After(eventTimeout):
+		t.Fatalf("watch of the secrets is not started")
+	}
+}
+
+func newTestInformer(clientSet kubernetes.Interface, opts ...Option) *Informer {
+	opts = append([]Option{WithDebounce(0)}, opts...)
+	return NewInformer(fake.NewClientBuilder().Build(), clientSet, testNamespace, opts...)
+}
+
+// watchTestSecrets starts watchers of the secrets and waits until the namespace informer is synced.
+func watchTestSecrets(t *testing.T, i *Informer, events chan<- Event, secretRefs ...types.NamespacedName) *WatchHandle {
+	t.Helper()
+	handle, err := i.WatchRetryable(context.Background(), secretRefs, func(event Event) error {
+		events <- event
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot watch secrets: %v", err)
+	}
+	t.Cleanup(handle.Stop)
+	deadline := time.Now().Add(eventTimeout)
+	for time.Now().Before(deadline) {
+		i.mutex.Lock()
+		nsInformer := i.namespaceInformers[testNamespace]
+		i.mutex.Unlock()
+		if nsInformer != nil && nsInformer.informer.HasSynced() {
+			return handle
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("namespace informer is not synced")
+	return nil
+}
+
+func updateTestSecret(t *testing.T, clientSet kubernetes.Interface, name string, data map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+	secret, err := clientSet.CoreV1().Secrets(testNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("cannot get %s secret: %v", name, err)
+	}
+	secret.Data = newTestSecret(name, data).Data
+	if _, err = clientSet.CoreV1().Secrets(testNamespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("cannot update %s secret: %v", name, err)
+	}
+}
+
+func waitEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(eventTimeout):
+		t.Fatalf("event is not received")
+		return Event{}
+	}
+}
+
+func expectNoEvent(t *testing.T, events <-chan Event, wait time.Duration) {
+	t.Helper()
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event: %+v", event)
+	case <-time.After(wait):
+	}
+}
+
+func getCachedSecret(i *Informer, secretRef types.NamespacedName) *corev1.Secret {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.namespaceInformers[secretRef.Namespace].getSecret(secretRef)
+}
+
+func TestSharedInformerStripsUnwatchedSecrets(t *testing.T) {
+	otherRef := types.NamespacedName{Namespace: testNamespace, Name: "helm-release"}
+	clientSet := newTestClientset(
+		newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}),
+		newTestSecret(otherRef.Name, map[string]string{"release": "payload"}))
+	i := newTestInformer(clientSet)
+	events := make(chan Event, 10)
+	watchTestSecrets(t, i, events, testSecretRef)
+	clientSet.waitWatch(t)
+
+	if cached := getCachedSecret(i, otherRef); cached == nil || !isStripped(cached) || len(cached.Data) > 0 {
+		t.Fatalf("unwatched secret must be cached without data: %+v", cached)
+	}
+	if cached := getCachedSecret(i, testSecretRef); cached == nil || isStripped(cached) || len(cached.Data) == 0 {
+		t.Fatalf("watched secret must be cached with data: %+v", cached)
+	}
+
+	updateTestSecret(t, clientSet, testSecretRef.Name, map[string]string{"password": "new-admin"})
+	event := waitEvent(t, events)
+	if event.Type != EventUpdated || !reflect.DeepEqual(event.ChangedKeys, []string{"password"}) {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
+
+func TestWatcherOfStrippedSecret(t *testing.T) {
+	otherRef := types.NamespacedName{Namespace: testNamespace, Name: "replication-credentials"}
+	clientSet := newTestClientset(
+		newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}),
+		newTestSecret(otherRef.Name, map[string]string{"password": "replicator", "username": "replicator"}))
+	i := newTestInformer(clientSet, WithResyncPeriod(time.Second))
+	events := make(chan Event, 10)
+	watchTestSecrets(t, i, events, testSecretRef)
+	clientSet.waitWatch(t)
+
+	// The second secret is cached without data before its watcher is created
+	watchTestSecrets(t, i, events, otherRef)
+	if cached := getCachedSecret(i, otherRef); cached == nil || !isStripped(cached) {
+		t.Fatalf("secret must stay cached without data until it is changed: %+v", cached)
+	}
+	// Resyncs of the secret cached without data don't report changes
+	expectNoEvent(t, events, 1500*time.Millisecond)
+
+	// The first change is compared with full version of the secret
+	updateTestSecret(t, clientSet, otherRef.Name, map[string]string{"password": "new-replicator", "username": "replicator"})
+	event := waitEvent(t, events)
+	if event.Secret != otherRef || !reflect.DeepEqual(event.ChangedKeys, []string{"password"}) ||
+		len(event.AddedKeys) > 0 || len(event.RemovedKeys) > 0 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	expectNoEvent(t, events, 300*time.Millisecond)
+}
@@ -0,0 +1,188 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// Runnable runs watchers of the secrets as controller-runtime manager runnable.
+// Watchers are started on the elected leader only and stopped when the manager context is cancelled on leadership loss.
+type Runnable struct {
+	informer   *Informer
+	secretRefs []types.NamespacedName
+	handler    RetryableEventHandler
+}
+
+// NewRunnable creates runnable of the provided secrets watchers for the default informer.
+func NewRunnable(secretNames []string, handler RetryableEventHandler) *Runnable {
+	informer := getDefaultInformer()
+	return informer.Runnable(utils.GetSecretRefs(secretNames, informer.namespace), handler)
+}
+
+// Runnable creates runnable of the provided secrets watchers, it must be added to the manager with mgr.Add.
+func (i *Informer) Runnable(secretRefs []types.NamespacedName, handler RetryableEventHandler) *Runnable {
+	return &Runnable{informer: i, secretRefs: secretRefs, handler: handler}
+}
+
+// Start starts watchers and blocks until the context is cancelled.
+// Watchers are stopped and handlers in progress are finished before return.
+func (r *Runnable) Start(ctx context.Context) error {
+	handle, err := r.informer.WatchRetryable(ctx, r.secretRefs, r.handler)
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	handle.Stop()
+	return nil
+}
+
+// NeedLeaderElection implements controller-runtime LeaderElectionRunnable, watchers run on the leader only.
+func (r *Runnable) NeedLeaderElection() bool {
+	return true
+}
+
+// LeaseConfig configures Lease based leader election of watchers.
+type LeaseConfig struct {
+	// Name of the Lease object
+	Name string
+	// Namespace of the Lease object, informer namespace is used if empty
+	Namespace string
+	// Identity of the candidate, lock.DefaultHolder() is used if empty
+	Identity string
+
+	// LeaseDuration, RenewDeadline and RetryPeriod have the same meaning as in client-go leader election,
+	// DefaultLeaseDuration, DefaultRenewDeadline and DefaultRetryPeriod are used if not set
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// RunWithLease runs watchers of the provided secrets for the default informer while Lease is held.
+func RunWithLease(ctx context.Context, config LeaseConfig, secretNames []string, handler RetryableEventHandler) error {
+	informer := getDefaultInformer()
+	return informer.RunWithLease(ctx, config, utils.GetSecretRefs(secretNames, informer.namespace), handler)
+}
+
+// RunWithLease runs watchers of the provided secrets while Lease is held by this candidate and blocks until the context is cancelled.
+// On leadership loss watchers are stopped and handlers in progress are finished before the candidate tries to acquire Lease again.
+// Lease is released on context cancellation.
+func (i *Informer) RunWithLease(ctx context.Context, config LeaseConfig, secretRefs []types.NamespacedName, handler RetryableEventHandler) error {
+	if handler == nil {
+		return fmt.Errorf("no reconcile function was provided")
+	}
+	if config.Name == "" {
+		return fmt.Errorf("lease name is not provided")
+	}
+	if config.Namespace == "" {
+		config.Namespace = i.namespace
+	}
+	if config.Identity == "" {
+		config.Identity = lock.DefaultHolder()
+	}
+	// Elector context is cancelled after watchers are stopped, so Lease is released only after handover is finished
+	electorCtx, cancelElector := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelElector()
+	var leading atomic.Bool
+	stopElector := context.AfterFunc(ctx, func() {
+		if !leading.Load() {
+			cancelElector()
+		}
+	})
+	defer stopElector()
+	// Elector run is cancelled when watchers are not started, so Lease is released and other candidates may take over
+	var cancelRun context.CancelFunc
+	var startFailed atomic.Bool
+	retryPeriod := durationOrDefault(config.RetryPeriod, DefaultRetryPeriod)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: config.Name, Namespace: config.Namespace},
+			Client:     i.clientSet.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: config.Identity},
+		},
+		LeaseDuration:   durationOrDefault(config.LeaseDuration, DefaultLeaseDuration),
+		RenewDeadline:   durationOrDefault(config.RenewDeadline, DefaultRenewDeadline),
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				leading.Store(true)
+				defer leading.Store(false)
+				watchCtx, cancel := context.WithCancel(leaderCtx)
+				defer cancel()
+				stopWatch := context.AfterFunc(ctx, cancel)
+				defer stopWatch()
+
+				logger.Info(fmt.Sprintf("Lease %s/%s is acquired by %s, watchers are started", config.Namespace, config.Name, config.Identity))
+				if err := i.Runnable(secretRefs, handler).Start(watchCtx); err != nil {
+					logger.Error(fmt.Sprintf("Cannot start watchers under lease %s/%s, the lease will be released", config.Namespace, config.Name), zap.Error(err))
+					startFailed.Store(true)
+					cancelRun()
+				}
+				if ctx.Err() != nil {
+					cancelElector()
+				}
+			},
+			OnStoppedLeading: func() {
+				logger.Info(fmt.Sprintf("Lease %s/%s is not held by %s, watchers are stopped", config.Namespace, config.Name, config.Identity))
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for electorCtx.Err() == nil {
+		var runCtx context.Context
+		runCtx, cancelRun = context.WithCancel(electorCtx)
+		startFailed.Store(false)
+		elector.Run(runCtx)
+		cancelRun()
+		// Leadership is lost, wait until watchers and handlers in progress are finished before the next attempt
+		i.Unwatch(secretRefs)
+		if startFailed.Load() {
+			select {
+			case <-electorCtx.Done():
+			case <-time.After(retryPeriod):
+			}
+		}
+	}
+	return nil
+}
+
+func durationOrDefault(duration, def time.Duration) time.Duration {
+	if duration <= 0 {
+		return def
+	}
+	return duration
+}
@@ -0,0 +1,129 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testLeaseName = "credential-manager"
+
+// testCandidate runs watchers of the secrets with the lease in background.
+type testCandidate struct {
+	events chan Event
+	cancel context.CancelFunc
+	doneCh chan error
+}
+
+func startCandidate(t *testing.T, clientSet *testClientset, identity string, secretRefs ...types.NamespacedName) *testCandidate {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	candidate := &testCandidate{events: make(chan Event, 10), cancel: cancel, doneCh: make(chan error, 1)}
+	config := LeaseConfig{
+		Name:          testLeaseName,
+		Identity:      identity,
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	}
+	i := newTestInformer(clientSet)
+	go func() {
+		candidate.doneCh <- i.RunWithLease(ctx, config, secretRefs, func(event Event) error {
+			candidate.events <- event
+			return nil
+		})
+	}()
+	t.Cleanup(candidate.stop)
+	return candidate
+}
+
+// stop cancels the candidate and waits until RunWithLease returns.
+func (c *testCandidate) stop() {
+	c.cancel()
+	if c.doneCh != nil {
+		<-c.doneCh
+		c.doneCh = nil
+	}
+}
+
+func getLeaseHolder(t *testing.T, clientSet *testClientset) string {
+	t.Helper()
+	lease, err := clientSet.CoordinationV1().Leases(testNamespace).Get(context.Background(), testLeaseName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return ""
+	} else if err != nil {
+		t.Fatalf("cannot get lease: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+func waitLeaseHolder(t *testing.T, clientSet *testClientset, identity string, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if getLeaseHolder(t, clientSet) == identity {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("lease is not acquired by %s in %v, holder: %q", identity, timeout, getLeaseHolder(t, clientSet))
+}
+
+func TestRunWithLeaseHandover(t *testing.T) {
+	clientSet := newTestClientset(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}))
+	first := startCandidate(t, clientSet, "first", testSecretRef)
+	waitLeaseHolder(t, clientSet, "first", eventTimeout)
+	clientSet.waitWatch(t)
+	second := startCandidate(t, clientSet, "second", testSecretRef)
+
+	updateTestSecret(t, clientSet, testSecretRef.Name, map[string]string{"password": "new-admin"})
+	waitEvent(t, first.events)
+	expectNoEvent(t, second.events, 300*time.Millisecond)
+
+	// The lease is released on cancellation, so the second candidate doesn't wait for lease expiration
+	first.stop()
+	if holder := getLeaseHolder(t, clientSet); holder == "first" {
+		t.Fatalf("lease must be released by stopped candidate")
+	}
+	waitLeaseHolder(t, clientSet, "second", time.Second)
+	clientSet.waitWatch(t)
+
+	updateTestSecret(t, clientSet, testSecretRef.Name, map[string]string{"password": "admin"})
+	waitEvent(t, second.events)
+	expectNoEvent(t, first.events, 300*time.Millisecond)
+}
+
+func TestRunWithLeaseReleasesOnStartFailure(t *testing.T) {
+	clientSet := newTestClientset(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}))
+	// Watchers of the first candidate cannot be started because of invalid secret reference
+	startCandidate(t, clientSet, "first", types.NamespacedName{Namespace: testNamespace})
+	waitLeaseHolder(t, clientSet, "first", eventTimeout)
+	second := startCandidate(t, clientSet, "second", testSecretRef)
+
+	// The second candidate takes over before the lease of the first one could expire
+	waitLeaseHolder(t, clientSet, "second", eventTimeout)
+	clientSet.waitWatch(t)
+	updateTestSecret(t, clientSet, testSecretRef.Name, map[string]string{"password": "new-admin"})
+	waitEvent(t, second.events)
+}
@@ -0,0 +1,112 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	watchedSecrets = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "credential_manager_watched_secrets",
+		Help: "Number of credentials secrets with active watcher",
+	})
+	watcherRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_watcher_restarts_total",
+		Help: "Number of watch restarts of credentials secrets informer",
+	}, []string{"namespace"})
+	reconcileFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_reconcile_failures_total",
+		Help: "Number of failed reconciles of credentials secret change",
+	}, []string{"namespace", "secret"})
+	retriesExhausted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_reconcile_retries_exhausted_total",
+		Help: "Number of credentials secret changes which reconcile retries were exhausted",
+	}, []string{"namespace", "secret"})
+	secretDeletions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_secret_deletions_total",
+		Help: "Number of deletions of watched credentials secrets",
+	}, []string{"namespace", "secret"})
+	secretLocks = newLockAgeCollector()
+)
+
+// Collectors returns Prometheus collectors of the informer package.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{watchedSecrets, watcherRestarts, reconcileFailures, retriesExhausted, secretDeletions, secretLocks}
+}
+
+// RegisterMetrics registers informer collectors, e.g. in controller-runtime metrics.Registry.
+func RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range Collectors() {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockAgeCollector reports time since lock acquisition for watched secrets.
+type lockAgeCollector struct {
+	desc       *prometheus.Desc
+	acquiredAt map[types.NamespacedName]time.Time
+	mutex      sync.Mutex
+}
+
+func newLockAgeCollector() *lockAgeCollector {
+	return &lockAgeCollector{
+		desc: prometheus.NewDesc("credential_manager_lock_age_seconds",
+			"Time since lock acquisition of locked credentials secret",
+			[]string{"namespace", "secret"}, nil),
+		acquiredAt: make(map[types.NamespacedName]time.Time),
+	}
+}
+
+func (c *lockAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *lockAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	for secretRef, acquiredAt := range c.acquiredAt {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue,
+			now.Sub(acquiredAt).Seconds(), secretRef.Namespace, secretRef.Name)
+	}
+}
+
+// observe tracks lock acquisition time of the secret, locks without acquisition time are not reported.
+func (c *lockAgeCollector) observe(secret *corev1.Secret) {
+	secretRef := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if record := lock.Get(secret); record != nil && !record.AcquiredAt.IsZero() {
+		c.acquiredAt[secretRef] = record.AcquiredAt
+	} else {
+		delete(c.acquiredAt, secretRef)
+	}
+}
+
+func (c *lockAgeCollector) forget(secretRef types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.acquiredAt, secretRef)
+}
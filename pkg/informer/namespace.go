@@ -0,0 +1,218 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// strippedAnnotation marks cached secrets without watcher, which are stored without data.
+const strippedAnnotation = "credentials-watch-stripped"
+
+// namespaceInformer is shared informer of the secrets in one namespace, events are dispatched to watchers by secret name.
+type namespaceInformer struct {
+	namespace string
+	informer  cache.SharedIndexInformer
+	watchers  int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// acquireNamespaceInformer returns running informer of the namespace, informer is started if it doesn't exist.
+// Must be called with the mutex held.
+func (i *Informer) acquireNamespaceInformer(namespace string) (*namespaceInformer, error) {
+	if nsInformer := i.namespaceInformers[namespace]; nsInformer != nil {
+		nsInformer.watchers++
+		return nsInformer, nil
+	}
+	nsInformer, err := i.newNamespaceInformer(namespace)
+	if err != nil {
+		return nil, err
+	}
+	nsInformer.watchers++
+	i.namespaceInformers[namespace] = nsInformer
+	go nsInformer.run()
+	return nsInformer, nil
+}
+
+// releaseNamespaceInformer decreases watchers number of the namespace informer.
+// Informer without watchers is removed and returned, it must be stopped without the mutex held.
+func (i *Informer) releaseNamespaceInformer(namespace string) *namespaceInformer {
+	nsInformer := i.namespaceInformers[namespace]
+	if nsInformer == nil {
+		return nil
+	}
+	nsInformer.watchers--
+	if nsInformer.watchers > 0 {
+		return nil
+	}
+	delete(i.namespaceInformers, namespace)
+	return nsInformer
+}
+
+func (i *Informer) newNamespaceInformer(namespace string) (*namespaceInformer, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	labelSelector := i.labelSelector
+	listWatch := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = labelSelector
+			return i.clientSet.CoreV1().Secrets(namespace).List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = labelSelector
+			return i.clientSet.CoreV1().Secrets(namespace).Watch(ctx, opts)
+		},
+	}
+	// Clients without watch list support, e.g. fake clientset, are switched to list and watch
+	informer := cache.NewSharedIndexInformer(
+		cache.ToListWatcherWithWatchListSemantics(listWatch, i.clientSet),
+		&corev1.Secret{},
+		i.resyncPeriod,
+		cache.Indexers{},
+	)
+	nsInformer := &namespaceInformer{namespace: namespace, informer: informer, ctx: ctx, cancel: cancel, doneCh: make(chan struct{})}
+
+	// Without label selector all secrets of the namespace are listed, so secrets without watcher are cached without data
+	if err := informer.SetTransform(i.stripUnwatched); err != nil {
+		cancel()
+		logger.Error("Cannot register secrets transform function", zap.Error(err))
+		return nil, err
+	}
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if watcher := i.watcherFor(obj); watcher != nil {
+				watcher.credsAddFunc(obj, isInInitialList)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if watcher := i.watcherFor(newObj); watcher != nil {
+				watcher.credsUpdFunc(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if watcher := i.watcherFor(obj); watcher != nil {
+				watcher.credsDelFunc(obj)
+			}
+		},
+	})
+	if err != nil {
+		cancel()
+		logger.Error("Cannot register credentials handler function", zap.Error(err))
+		return nil, err
+	}
+	err = informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		watcherRestarts.WithLabelValues(namespace).Inc()
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+	})
+	if err != nil {
+		cancel()
+		logger.Error("Cannot register watch error handler function", zap.Error(err))
+		return nil, err
+	}
+	return nsInformer, nil
+}
+
+// stripUnwatched replaces secret without watcher with its identity, so unrelated secrets, e.g. Helm releases, don't consume memory.
+func (i *Informer) stripUnwatched(obj interface{}) (interface{}, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || i.getWatcher(types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}) != nil {
+		return obj, nil
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secret.Name,
+			Namespace:       secret.Namespace,
+			UID:             secret.UID,
+			ResourceVersion: secret.ResourceVersion,
+			Annotations:     map[string]string{strippedAnnotation: "true"},
+		},
+	}, nil
+}
+
+func isStripped(secret *corev1.Secret) bool {
+	return secret.Annotations[strippedAnnotation] == "true"
+}
+
+// strippedSecrets returns secrets without watcher, which are cached without data by running namespace informers.
+func (i *Informer) strippedSecrets(secretRefs []types.NamespacedName) []types.NamespacedName {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	var stripped []types.NamespacedName
+	for _, secretRef := range secretRefs {
+		nsInformer := i.namespaceInformers[secretRef.Namespace]
+		if nsInformer == nil || i.activeWatchers[secretRef] != nil {
+			continue
+		}
+		if cached := nsInformer.getSecret(secretRef); cached != nil && isStripped(cached) {
+			stripped = append(stripped, secretRef)
+		}
+	}
+	return stripped
+}
+
+// fetchSecret returns full version of the secret from the API, nil is returned if the secret cannot be read.
+func (i *Informer) fetchSecret(ctx context.Context, secretRef types.NamespacedName) *corev1.Secret {
+	secret, err := i.clientSet.CoreV1().Secrets(secretRef.Namespace).Get(ctx, secretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Cannot get secret %s cached without data", secretRef), zap.Error(err))
+		return nil
+	}
+	return secret
+}
+
+func (i *Informer) watcherFor(obj interface{}) *Watcher {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+	return i.getWatcher(types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
+}
+
+func (n *namespaceInformer) run() {
+	defer close(n.doneCh)
+	logger.Info("Creds informer started", zap.String("namespace", n.namespace))
+	n.informer.RunWithContext(n.ctx)
+	logger.Info("Creds informer finished", zap.String("namespace", n.namespace))
+}
+
+// getSecret returns cached version of the secret, nil is returned if the secret is not cached.
+func (n *namespaceInformer) getSecret(secretRef types.NamespacedName) *corev1.Secret {
+	obj, exists, err := n.informer.GetStore().GetByKey(secretRef.String())
+	if err != nil || !exists {
+		return nil
+	}
+	secret, _ := obj.(*corev1.Secret)
+	return secret
+}
+
+func (n *namespaceInformer) stop() {
+	n.cancel()
+	<-n.doneCh
+}
@@ -0,0 +1,70 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RecreationPolicy defines how creation of the watched secret after the watcher start is handled.
+type RecreationPolicy string
+
+const (
+	// RecreationIgnore skips creation of the secret, it is the default policy
+	RecreationIgnore RecreationPolicy = "ignore"
+	// RecreationRotate treats creation of the secret with credentials different from the deleted version as credentials change
+	RecreationRotate RecreationPolicy = "rotate"
+)
+
+// DeletionPolicy defines how deletion of the watched secret is handled.
+type DeletionPolicy string
+
+const (
+	// DeletionIgnore only logs deletion of the secret, it is the default policy
+	DeletionIgnore DeletionPolicy = "ignore"
+	// DeletionAlert posts Warning Event, increases deletions metric and passes EventDeleted to the handler
+	DeletionAlert DeletionPolicy = "alert"
+	// DeletionCleanup treats deletion as intentional removal, the same as DeletionAlert, but also deletes -old copy of the secret
+	DeletionCleanup DeletionPolicy = "cleanup"
+)
+
+// cleanupOldSecret deletes -old copy of the deleted secret. Copy is kept if the secret exists again.
+func (i *Informer) cleanupOldSecret(ctx context.Context, secretRef types.NamespacedName) error {
+	_, err := i.clientSet.CoreV1().Secrets(secretRef.Namespace).Get(ctx, secretRef.Name, metav1.GetOptions{})
+	if err == nil {
+		logger.Info(fmt.Sprintf("Secret %s exists, its old copy is kept", secretRef))
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	oldSecretRef := utils.GetOldSecretRef(secretRef)
+	err = i.clientSet.CoreV1().Secrets(oldSecretRef.Namespace).Delete(ctx, oldSecretRef.Name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	i.recorder.Normal(secretRef, recorder.ReasonOldCopyDeleted, "Old copy %s of deleted secret was removed", oldSecretRef.Name)
+	logger.Info(fmt.Sprintf("Old copy %s of deleted secret %s was removed", oldSecretRef, secretRef))
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func oldCopyExists(t *testing.T, clientSet *testClientset) bool {
+	t.Helper()
+	oldSecretRef := utils.GetOldSecretRef(testSecretRef)
+	_, err := clientSet.CoreV1().Secrets(oldSecretRef.Namespace).Get(context.Background(), oldSecretRef.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		t.Fatalf("cannot get old copy: %v", err)
+	}
+	return err == nil
+}
+
+func deleteTestSecret(t *testing.T, clientSet *testClientset) {
+	t.Helper()
+	err := clientSet.CoreV1().Secrets(testNamespace).Delete(context.Background(), testSecretRef.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("cannot delete secret: %v", err)
+	}
+}
+
+func createTestSecret(t *testing.T, clientSet *testClientset, data map[string]string) {
+	t.Helper()
+	_, err := clientSet.CoreV1().Secrets(testNamespace).Create(context.Background(), newTestSecret(testSecretRef.Name, data), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("cannot create secret: %v", err)
+	}
+}
+
+func TestDeletionPolicies(t *testing.T) {
+	tests := []struct {
+		policy      DeletionPolicy
+		event       bool
+		keepOldCopy bool
+	}{
+		{policy: DeletionIgnore, keepOldCopy: true},
+		{policy: DeletionAlert, event: true, keepOldCopy: true},
+		{policy: DeletionCleanup, event: true},
+	}
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			clientSet := newTestClientset(
+				newTestSecret(testSecretRef.Name, map[string]string{"password": "admin", "username": "admin"}),
+				newTestSecret(utils.GetOldSecretName(testSecretRef.Name), map[string]string{"password": "admin", "username": "admin"}))
+			i := newTestInformer(clientSet, WithDeletionPolicy(test.policy))
+			events := make(chan Event, 10)
+			watchTestSecrets(t, i, events, testSecretRef)
+			clientSet.waitWatch(t)
+
+			deleteTestSecret(t, clientSet)
+			if test.event {
+				event := waitEvent(t, events)
+				if event.Type != EventDeleted || !reflect.DeepEqual(event.RemovedKeys, []string{"password", "username"}) {
+					t.Fatalf("unexpected event: %+v", event)
+				}
+			} else {
+				expectNoEvent(t, events, 300*time.Millisecond)
+			}
+			if exists := oldCopyExists(t, clientSet); exists != test.keepOldCopy {
+				t.Fatalf("old copy exists: %t, expected: %t", exists, test.keepOldCopy)
+			}
+		})
+	}
+}
+
+func TestCleanupKeepsOldCopyOfExistingSecret(t *testing.T) {
+	clientSet := newTestClientset(
+		newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}),
+		newTestSecret(utils.GetOldSecretName(testSecretRef.Name), map[string]string{"password": "admin"}))
+	i := newTestInformer(clientSet, WithDeletionPolicy(DeletionCleanup))
+	if err := i.cleanupOldSecret(context.Background(), testSecretRef); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !oldCopyExists(t, clientSet) {
+		t.Fatalf("old copy of existing secret must be kept")
+	}
+}
+
+func TestRecreationPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RecreationPolicy
+		data     map[string]string
+		expected *Event
+	}{
+		{
+			name:   "ignore",
+			policy: RecreationIgnore,
+			data:   map[string]string{"password": "new-admin", "username": "admin"},
+		},
+		{
+			name:     "rotate changed credentials",
+			policy:   RecreationRotate,
+			data:     map[string]string{"password": "new-admin", "username": "admin"},
+			expected: &Event{Type: EventAdded, SecretDiff: utils.SecretDiff{ChangedKeys: []string{"password"}}},
+		},
+		{
+			name:   "rotate the same credentials",
+			policy: RecreationRotate,
+			data:   map[string]string{"password": "admin", "username": "admin"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientSet := newTestClientset(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin", "username": "admin"}))
+			i := newTestInformer(clientSet, WithRecreationPolicy(test.policy))
+			events := make(chan Event, 10)
+			watchTestSecrets(t, i, events, testSecretRef)
+			clientSet.waitWatch(t)
+
+			deleteTestSecret(t, clientSet)
+			createTestSecret(t, clientSet, test.data)
+			if test.expected == nil {
+				expectNoEvent(t, events, 300*time.Millisecond)
+				return
+			}
+			event := waitEvent(t, events)
+			if event.Type != test.expected.Type || !reflect.DeepEqual(event.SecretDiff, test.expected.SecretDiff) {
+				t.Fatalf("unexpected event: %+v", event)
+			}
+		})
+	}
+}
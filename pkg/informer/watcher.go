@@ -0,0 +1,212 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Watcher receives changes of one secret from the shared informer of the secret namespace.
+type Watcher struct {
+	secretRef  types.NamespacedName
+	handler    RetryableEventHandler
+	owner      *Informer
+	dispatcher *dispatcher
+	// deleted is the last known version of the deleted secret, it is accessed from informer handlers only
+	deleted *corev1.Secret
+	// baseline is full version of the secret cached without data, it is accessed from informer handlers only after start
+	baseline *corev1.Secret
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+func newWatcher(ctx context.Context, owner *Informer, secretRef types.NamespacedName, handler RetryableEventHandler, dispatcher *dispatcher) *Watcher {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Watcher{
+		secretRef:  secretRef,
+		handler:    handler,
+		owner:      owner,
+		dispatcher: dispatcher,
+		ctx:        ctx,
+		cancel:     cancel,
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// run keeps the watcher active until it is stopped or its context is cancelled.
+func (w *Watcher) run(nsInformer *namespaceInformer) {
+	// Prepare watcher clean
+	defer func() {
+		w.owner.removeWatcher(w)
+		secretLocks.forget(w.secretRef)
+		w.cancel()
+		close(w.doneCh)
+	}()
+
+	//Start active watcher
+	logger.Info(fmt.Sprintf("Creds watcher for secret %s started", w.secretRef))
+	if secret := w.known(nsInformer.getSecret(w.secretRef)); secret != nil {
+		secretLocks.observe(secret)
+	}
+	<-w.ctx.Done()
+	logger.Info(fmt.Sprintf("Creds watcher for secret %s finished", w.secretRef))
+}
+
+// Stop stops the watcher and waits until it is finished.
+func (w *Watcher) Stop() {
+	w.cancel()
+	<-w.doneCh
+}
+
+// WatchHandle controls watchers started by Watch call.
+type WatchHandle struct {
+	watchers []*Watcher
+}
+
+// Stop stops watchers started by Watch call and waits until they are finished.
+func (h *WatchHandle) Stop() {
+	if h == nil {
+		return
+	}
+	for _, watcher := range h.watchers {
+		watcher.Stop()
+	}
+}
+
+// known returns the secret or its baseline if the secret is cached without data, nil is returned if full version is unknown.
+func (w *Watcher) known(secret *corev1.Secret) *corev1.Secret {
+	if secret == nil || !isStripped(secret) {
+		return secret
+	}
+	return w.baseline
+}
+
+func (w *Watcher) credsAddFunc(obj interface{}, isInInitialList bool) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		logger.Error("created watched credentials secret is not Secret object")
+		return
+	}
+	// The secret was received before the watcher was created, so full version is requested
+	if isStripped(secret) {
+		if secret = w.owner.fetchSecret(w.ctx, w.secretRef); secret == nil {
+			return
+		}
+		w.baseline = secret
+	}
+	secretLocks.observe(secret)
+	deleted := w.deleted
+	w.deleted = nil
+	if isInInitialList {
+		return
+	}
+	if w.owner.recreationPolicy != RecreationRotate {
+		logger.Info(fmt.Sprintf("Creds secret %s was created, change is ignored", w.secretRef))
+		return
+	}
+	if event, changed := DetectRecreation(deleted, secret, w.owner.diffOptions); changed {
+		w.owner.recorder.Normal(event.Secret, recorder.ReasonSecretRecreated, "Credentials secret was created, added keys: %v, removed keys: %v, changed keys: %v",
+			event.AddedKeys, event.RemovedKeys, event.ChangedKeys)
+		logger.Info("Creds secret was created, reconcile is scheduled",
+			zap.String("secret", event.Secret.String()),
+			zap.Strings("addedKeys", event.AddedKeys),
+			zap.Strings("removedKeys", event.RemovedKeys),
+			zap.Strings("changedKeys", event.ChangedKeys),
+			zap.String("resourceVersion", event.NewResourceVersion))
+		w.dispatcher.enqueue(event)
+	}
+}
+
+func (w *Watcher) credsDelFunc(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		logger.Error("deleted watched credentials secret is not Secret object")
+		return
+	}
+	if known := w.known(secret); known != nil {
+		secret = known
+	}
+	secretLocks.forget(w.secretRef)
+	w.deleted = secret
+	if w.owner.deletionPolicy != DeletionAlert && w.owner.deletionPolicy != DeletionCleanup {
+		logger.Info(fmt.Sprintf("Creds secret %s was deleted, deletion is ignored", w.secretRef))
+		return
+	}
+	secretDeletions.WithLabelValues(w.secretRef.Namespace, w.secretRef.Name).Inc()
+	w.owner.recorder.Warning(w.secretRef, recorder.ReasonSecretDeleted, "Watched credentials secret was deleted")
+	logger.Error(fmt.Sprintf("Watched creds secret %s was deleted", w.secretRef),
+		zap.String("resourceVersion", secret.ResourceVersion))
+	w.dispatcher.enqueue(newDeletedEvent(secret, w.owner.diffOptions))
+}
+
+func (w *Watcher) credsUpdFunc(oldObj, newObj interface{}) {
+	oldSecret, ok := oldObj.(*corev1.Secret)
+	if !ok {
+		errMsg := "old watched credentials secret is not Secret object"
+		logger.Error(errMsg)
+		return
+	}
+	newSecret, ok := newObj.(*corev1.Secret)
+	if !ok {
+		errMsg := "new watched credentials secret is not Secret object"
+		logger.Error(errMsg)
+		return
+	}
+	refreshed := false
+	if isStripped(newSecret) {
+		// Resync of the secret cached without data contains no changes
+		if newSecret.ResourceVersion == oldSecret.ResourceVersion {
+			return
+		}
+		// The change was received before the watcher was created, so full version is requested
+		if newSecret = w.owner.fetchSecret(w.ctx, w.secretRef); newSecret == nil {
+			return
+		}
+		refreshed = true
+	}
+	secretLocks.observe(newSecret)
+	oldSecret = w.known(oldSecret)
+	if refreshed {
+		w.baseline = newSecret
+	}
+	if oldSecret == nil {
+		logger.Info(fmt.Sprintf("Previous version of secret %s is unknown, change is skipped", w.secretRef))
+		return
+	}
+	if event, changed := DetectChange(oldSecret, newSecret, w.owner.diffOptions); changed {
+		if event.LockState == lock.StateExpired {
+			w.owner.recorder.Warning(event.Secret, recorder.ReasonLockExpired, "Lock held by %s is expired, credentials change is processed",
+				event.Lock.Holder)
+		}
+		w.owner.recorder.Normal(event.Secret, recorder.ReasonChanged, "Credentials change detected, added keys: %v, removed keys: %v, changed keys: %v",
+			event.AddedKeys, event.RemovedKeys, event.ChangedKeys)
+		logger.Info("New credentials found, reconcile is scheduled",
+			zap.String("secret", event.Secret.String()),
+			zap.Strings("addedKeys", event.AddedKeys),
+			zap.Strings("removedKeys", event.RemovedKeys),
+			zap.Strings("changedKeys", event.ChangedKeys),
+			zap.String("resourceVersion", event.NewResourceVersion))
+		w.dispatcher.enqueue(event)
+	}
+}
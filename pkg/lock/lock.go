@@ -0,0 +1,143 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lock
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	HolderAnnotation     = utils.LockLabel + "-holder"
+	AcquiredAtAnnotation = utils.LockLabel + "-acquired-at"
+	TTLAnnotation        = utils.LockLabel + "-ttl"
+
+	DefaultTTL = 1 * time.Hour
+)
+
+// Record describes lock of the credentials secret.
+type Record struct {
+	Holder     string
+	AcquiredAt time.Time
+	TTL        time.Duration
+}
+
+// ExpiresAt returns lock expiration time. Zero time is returned for locks without acquisition time.
+func (r *Record) ExpiresAt() time.Time {
+	if r.AcquiredAt.IsZero() {
+		return time.Time{}
+	}
+	return r.AcquiredAt.Add(r.TTL)
+}
+
+// IsExpired checks if lock is expired at the provided time.
+// Locks without acquisition time, e.g. created by previous versions, are always expired.
+func (r *Record) IsExpired(now time.Time) bool {
+	expiresAt := r.ExpiresAt()
+	return expiresAt.IsZero() || !now.Before(expiresAt)
+}
+
+// Get returns lock record of the secret, nil is returned if secret is not locked.
+// Locks created without holder and acquisition time are returned with zero AcquiredAt, DefaultTTL is used for locks without TTL.
+func Get(secret *corev1.Secret) *Record {
+	if secret.Annotations[utils.LockLabel] != "true" {
+		return nil
+	}
+	record := &Record{Holder: secret.Annotations[HolderAnnotation], TTL: DefaultTTL}
+	if acquiredAt, err := time.Parse(time.RFC3339, secret.Annotations[AcquiredAtAnnotation]); err == nil {
+		record.AcquiredAt = acquiredAt
+	}
+	if ttl, err := time.ParseDuration(secret.Annotations[TTLAnnotation]); err == nil && ttl > 0 {
+		record.TTL = ttl
+	}
+	return record
+}
+
+// IsLocked checks if the secret has lock which is not expired at the provided time.
+func IsLocked(secret *corev1.Secret, now time.Time) bool {
+	record := Get(secret)
+	return record != nil && !record.IsExpired(now)
+}
+
+// IsExpired checks if the secret has lock which is expired at the provided time.
+func IsExpired(secret *corev1.Secret, now time.Time) bool {
+	record := Get(secret)
+	return record != nil && record.IsExpired(now)
+}
+
+// State describes lock state of the secret.
+type State string
+
+const (
+	StateUnlocked State = "Unlocked"
+	StateLocked   State = "Locked"
+	StateExpired  State = "Expired"
+)
+
+// GetState returns lock state of the secret at the provided time.
+func GetState(secret *corev1.Secret, now time.Time) State {
+	record := Get(secret)
+	switch {
+	case record == nil:
+		return StateUnlocked
+	case record.IsExpired(now):
+		return StateExpired
+	default:
+		return StateLocked
+	}
+}
+
+// Acquire sets lock annotations on the secret.
+func Acquire(secret *corev1.Secret, holder string, ttl time.Duration, now time.Time) {
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[utils.LockLabel] = "true"
+	secret.Annotations[HolderAnnotation] = holder
+	secret.Annotations[AcquiredAtAnnotation] = now.UTC().Format(time.RFC3339)
+	secret.Annotations[TTLAnnotation] = ttl.String()
+}
+
+// Release sets lock annotation to "false" and removes lock record from the secret.
+func Release(secret *corev1.Secret) {
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[utils.LockLabel] = "false"
+	delete(secret.Annotations, HolderAnnotation)
+	delete(secret.Annotations, AcquiredAtAnnotation)
+	delete(secret.Annotations, TTLAnnotation)
+}
+
+// DefaultHolder returns lock holder identity from POD_NAME environment variable or host name.
+func DefaultHolder() string {
+	if podName := os.Getenv("POD_NAME"); podName != "" {
+		return podName
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Sprintf("pid-%d", os.Getpid())
+	}
+	return hostname
+}
+
+// GetTTL returns lock TTL from LOCK_TTL environment variable or DefaultTTL.
+func GetTTL() time.Duration {
+	return utils.GetEnvDuration("LOCK_TTL", DefaultTTL)
+}
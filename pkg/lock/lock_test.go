@@ -0,0 +1,194 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testNow = time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+func newSecret(annotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Annotations: annotations}}
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *Record
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name:        "released lock",
+			annotations: map[string]string{utils.LockLabel: "false"},
+		},
+		{
+			name: "full record",
+			annotations: map[string]string{
+				utils.LockLabel:      "true",
+				HolderAnnotation:     "operator-0",
+				AcquiredAtAnnotation: "2026-01-02T09:30:00Z",
+				TTLAnnotation:        "30m",
+			},
+			want: &Record{Holder: "operator-0", AcquiredAt: testNow.Add(-30 * time.Minute), TTL: 30 * time.Minute},
+		},
+		{
+			name:        "legacy lock",
+			annotations: map[string]string{utils.LockLabel: "true"},
+			want:        &Record{TTL: DefaultTTL},
+		},
+		{
+			name: "invalid time and ttl",
+			annotations: map[string]string{
+				utils.LockLabel:      "true",
+				AcquiredAtAnnotation: "yesterday",
+				TTLAnnotation:        "-1m",
+			},
+			want: &Record{TTL: DefaultTTL},
+		},
+		{
+			name: "zero ttl",
+			annotations: map[string]string{
+				utils.LockLabel:      "true",
+				AcquiredAtAnnotation: "2026-01-02T09:30:00Z",
+				TTLAnnotation:        "0s",
+			},
+			want: &Record{AcquiredAt: testNow.Add(-30 * time.Minute), TTL: DefaultTTL},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Get(newSecret(tt.annotations)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordIsExpired(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		want   bool
+	}{
+		{
+			name:   "active",
+			record: Record{AcquiredAt: testNow.Add(-time.Minute), TTL: time.Hour},
+		},
+		{
+			name:   "expires now",
+			record: Record{AcquiredAt: testNow.Add(-time.Hour), TTL: time.Hour},
+			want:   true,
+		},
+		{
+			name:   "expired",
+			record: Record{AcquiredAt: testNow.Add(-2 * time.Hour), TTL: time.Hour},
+			want:   true,
+		},
+		{
+			name:   "legacy lock",
+			record: Record{TTL: DefaultTTL},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.record.IsExpired(testNow); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetState(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        State
+		locked      bool
+		expired     bool
+	}{
+		{
+			name: "unlocked",
+			want: StateUnlocked,
+		},
+		{
+			name: "locked",
+			annotations: map[string]string{
+				utils.LockLabel:      "true",
+				AcquiredAtAnnotation: "2026-01-02T09:30:00Z",
+				TTLAnnotation:        "1h",
+			},
+			want:   StateLocked,
+			locked: true,
+		},
+		{
+			name: "expired",
+			annotations: map[string]string{
+				utils.LockLabel:      "true",
+				AcquiredAtAnnotation: "2026-01-02T08:30:00Z",
+				TTLAnnotation:        "1h",
+			},
+			want:    StateExpired,
+			expired: true,
+		},
+		{
+			name:        "legacy lock",
+			annotations: map[string]string{utils.LockLabel: "true"},
+			want:        StateExpired,
+			expired:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := newSecret(tt.annotations)
+			if got := GetState(secret, testNow); got != tt.want {
+				t.Errorf("GetState() = %v, want %v", got, tt.want)
+			}
+			if got := IsLocked(secret, testNow); got != tt.locked {
+				t.Errorf("IsLocked() = %v, want %v", got, tt.locked)
+			}
+			if got := IsExpired(secret, testNow); got != tt.expired {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.expired)
+			}
+		})
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	secret := newSecret(nil)
+	Acquire(secret, "operator-0", 30*time.Minute, testNow)
+	want := &Record{Holder: "operator-0", AcquiredAt: testNow, TTL: 30 * time.Minute}
+	if got := Get(secret); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Get() after Acquire = %+v, want %+v", got, want)
+	}
+
+	Release(secret)
+	if got := Get(secret); got != nil {
+		t.Fatalf("Get() after Release = %+v, want nil", got)
+	}
+	if len(secret.Annotations) != 1 || secret.Annotations[utils.LockLabel] != "false" {
+		t.Fatalf("unexpected annotations after Release: %v", secret.Annotations)
+	}
+}
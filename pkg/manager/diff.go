@@ -0,0 +1,170 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	passwordSuffix = "password"
+)
+
+var usernameSuffixes = []string{"username", "user", "login"}
+
+// CredsDiff describes credentials change between "-old" copy and the actual secret.
+type CredsDiff struct {
+	// SecretDiff contains added, removed and changed keys
+	utils.SecretDiff
+
+	NewSecret *corev1.Secret
+	OldSecret *corev1.Secret
+
+	// comparable is NewSecret converted into the form of OldSecret by the previous credentials store
+	comparable *corev1.Secret
+}
+
+// Credential describes username and password pair found in the secret.
+// Username key is empty for passwords without paired username key.
+type Credential struct {
+	UsernameKey string
+	PasswordKey string
+
+	Username    string
+	OldUsername string
+	Password    string
+	OldPassword string
+
+	// Added is true if the pair is absent in the old secret
+	Added bool
+	// Removed is true if the pair is absent in the new secret
+	Removed bool
+	// UsernameChanged is true if username key was added, removed or changed
+	UsernameChanged bool
+	// PasswordChanged is true if password key was added, removed or changed
+	PasswordChanged bool
+}
+
+// IsChanged checks if any of the credential keys was changed.
+func (c Credential) IsChanged() bool {
+	return c.UsernameChanged || c.PasswordChanged
+}
+
+func newCredsDiff(oldSecret, newSecret, comparable *corev1.Secret, diff utils.SecretDiff) *CredsDiff {
+	return &CredsDiff{SecretDiff: diff, NewSecret: newSecret, OldSecret: oldSecret, comparable: comparable}
+}
+
+// ComparableSecret returns NewSecret in the form of OldSecret, e.g. hashed by HashStore, so its values may be compared with OldSecret.
+func (d *CredsDiff) ComparableSecret() *corev1.Secret {
+	if d.comparable == nil {
+		return d.NewSecret
+	}
+	return d.comparable
+}
+
+// IsKeyChanged checks if the key was added, removed or changed.
+func (d *CredsDiff) IsKeyChanged(key string) bool {
+	return slices.Contains(d.AddedKeys, key) || slices.Contains(d.RemovedKeys, key) || slices.Contains(d.ChangedKeys, key)
+}
+
+// Credentials returns all username and password pairs found in the old and new secrets.
+// Pairs are detected by keys with the same prefix and "password" and "username", "user" or "login" suffixes,
+// e.g. "password" and "username" or "replication-password" and "replication-user".
+func (d *CredsDiff) Credentials() []Credential {
+	keys := d.allKeys()
+	credentials := make([]Credential, 0)
+	for _, key := range keys {
+		lowerKey := strings.ToLower(key)
+		if !strings.HasSuffix(lowerKey, passwordSuffix) {
+			continue
+		}
+		prefix := lowerKey[:len(lowerKey)-len(passwordSuffix)]
+		credentials = append(credentials, d.credential(findUsernameKey(keys, prefix), key))
+	}
+	return credentials
+}
+
+// ChangedCredentials returns username and password pairs with added, removed or changed keys.
+func (d *CredsDiff) ChangedCredentials() []Credential {
+	changed := make([]Credential, 0)
+	for _, credential := range d.Credentials() {
+		if credential.IsChanged() {
+			changed = append(changed, credential)
+		}
+	}
+	return changed
+}
+
+// ForEachChangedCredential calls fn for each changed username and password pair and stops on the first error.
+func (d *CredsDiff) ForEachChangedCredential(fn func(credential Credential) error) error {
+	for _, credential := range d.ChangedCredentials() {
+		if err := fn(credential); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *CredsDiff) credential(usernameKey, passwordKey string) Credential {
+	_, newFound := d.NewSecret.Data[passwordKey]
+	_, oldFound := d.OldSecret.Data[passwordKey]
+	credential := Credential{
+		UsernameKey:     usernameKey,
+		PasswordKey:     passwordKey,
+		Password:        string(d.NewSecret.Data[passwordKey]),
+		OldPassword:     string(d.OldSecret.Data[passwordKey]),
+		Added:           newFound && !oldFound,
+		Removed:         !newFound && oldFound,
+		PasswordChanged: d.IsKeyChanged(passwordKey),
+	}
+	if usernameKey != "" {
+		credential.Username = string(d.NewSecret.Data[usernameKey])
+		credential.OldUsername = string(d.OldSecret.Data[usernameKey])
+		credential.UsernameChanged = d.IsKeyChanged(usernameKey)
+	}
+	return credential
+}
+
+func (d *CredsDiff) allKeys() []string {
+	keySet := make(map[string]struct{})
+	for key := range d.NewSecret.Data {
+		keySet[key] = struct{}{}
+	}
+	for key := range d.OldSecret.Data {
+		keySet[key] = struct{}{}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func findUsernameKey(keys []string, prefix string) string {
+	for _, suffix := range usernameSuffixes {
+		for _, key := range keys {
+			if strings.ToLower(key) == prefix+suffix {
+				return key
+			}
+		}
+	}
+	return ""
+}
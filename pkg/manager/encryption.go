@@ -0,0 +1,253 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StoreEncrypted keeps previous credentials in "-old" secret encrypted with AES-GCM
+	StoreEncrypted = "encrypted"
+
+	// EncryptionKeyIDAnnotation contains ID of the key which encrypts data key of "-old" secret
+	EncryptionKeyIDAnnotation = "credentials-encryption-key-id"
+	// EncryptedDataKeyAnnotation contains encrypted data key of "-old" secret
+	EncryptedDataKeyAnnotation = "credentials-encrypted-data-key"
+	// ActiveKeyIDAnnotation may be set on the key secret to select key used for encryption
+	ActiveKeyIDAnnotation = "credentials-active-key-id"
+)
+
+// EncryptedStore keeps previous credentials in "-old" secret encrypted with envelope encryption:
+// values are encrypted with AES-GCM using random data key, which is encrypted with the key from the key secret.
+// Key secret contains keys of 16, 24 or 32 bytes by their IDs, so keys may be rotated without losing existing copies.
+// Previous credentials are decrypted on load, so changeCredsFunc and comparison receive plain values.
+// Copies with plain data are encrypted on the next save.
+type EncryptedStore struct {
+	secrets      *SecretStore
+	client       client.Client
+	keySecretRef types.NamespacedName
+	activeKeyID  string
+}
+
+// NewEncryptedStore creates store which encrypts previous credentials with keys from the key secret.
+// If activeKeyID is empty, key from ActiveKeyIDAnnotation of the key secret is used, or the only key of the secret.
+func NewEncryptedStore(k8sClient client.Client, keySecretRef types.NamespacedName, activeKeyID string) *EncryptedStore {
+	return &EncryptedStore{
+		secrets:      NewSecretStore(k8sClient),
+		client:       k8sClient,
+		keySecretRef: keySecretRef,
+		activeKeyID:  activeKeyID,
+	}
+}
+
+// Load returns "-old" copy of the secret with decrypted data.
+func (s *EncryptedStore) Load(ctx context.Context, secretRef types.NamespacedName) (*corev1.Secret, error) {
+	previous, err := s.secrets.get(ctx, secretRef)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.decrypt(ctx, previous); err != nil {
+		return nil, fmt.Errorf("cannot decrypt previous credentials of secret %s: %w", secretRef, err)
+	}
+	return previous, nil
+}
+
+// Save creates or updates "-old" copy of the secret, data is encrypted with the active key.
+func (s *EncryptedStore) Save(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret)) error {
+	return s.secrets.save(ctx, secretRef, func(previous *corev1.Secret) error {
+		return s.reencrypt(ctx, previous, func(previous *corev1.Secret) error {
+			mutate(previous)
+			return nil
+		})
+	})
+}
+
+// Update updates existing "-old" copy of the secret, data is encrypted with the active key.
+func (s *EncryptedStore) Update(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret) error) error {
+	return s.secrets.update(ctx, secretRef, func(previous *corev1.Secret) error {
+		return s.reencrypt(ctx, previous, mutate)
+	})
+}
+
+// Comparable returns current version of the secret as is, previous version is decrypted on load.
+func (s *EncryptedStore) Comparable(_, current *corev1.Secret) *corev1.Secret {
+	return current
+}
+
+// Reencrypt encrypts "-old" copy of the secret with the active key, e.g. after key rotation.
+func (s *EncryptedStore) Reencrypt(ctx context.Context, secretRef types.NamespacedName) error {
+	return s.Update(ctx, secretRef, func(*corev1.Secret) error {
+		return nil
+	})
+}
+
+// reencrypt decrypts data of the copy, applies mutate function and encrypts data with new data key.
+func (s *EncryptedStore) reencrypt(ctx context.Context, previous *corev1.Secret, mutate func(previous *corev1.Secret) error) error {
+	if err := s.decrypt(ctx, previous); err != nil {
+		return err
+	}
+	if err := mutate(previous); err != nil {
+		return err
+	}
+	return s.encrypt(ctx, previous)
+}
+
+func (s *EncryptedStore) encrypt(ctx context.Context, secret *corev1.Secret) error {
+	keys, err := s.getKeys(ctx)
+	if err != nil {
+		return err
+	}
+	keyID, err := s.getActiveKeyID(keys)
+	if err != nil {
+		return err
+	}
+	dataKey := make([]byte, 32)
+	if _, err = rand.Read(dataKey); err != nil {
+		return err
+	}
+	encryptedDataKey, err := seal(keys.Data[keyID], dataKey, []byte(keyID))
+	if err != nil {
+		return fmt.Errorf("cannot encrypt data key with key %s: %w", keyID, err)
+	}
+	// Data map may be shared with the current version of the secret
+	data := make(map[string][]byte, len(secret.Data))
+	for key, value := range secret.Data {
+		if data[key], err = seal(dataKey, value, valueAAD(secret, key)); err != nil {
+			return err
+		}
+	}
+	secret.Data = data
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[PreviousFormatAnnotation] = StoreEncrypted
+	secret.Annotations[EncryptionKeyIDAnnotation] = keyID
+	secret.Annotations[EncryptedDataKeyAnnotation] = base64.StdEncoding.EncodeToString(encryptedDataKey)
+	return nil
+}
+
+// decrypt replaces encrypted data of the copy with plain values, copy with plain data is not changed.
+func (s *EncryptedStore) decrypt(ctx context.Context, secret *corev1.Secret) error {
+	switch format := secret.Annotations[PreviousFormatAnnotation]; format {
+	case "":
+		return nil
+	case StoreEncrypted:
+	default:
+		return fmt.Errorf("previous credentials are stored in %s format", format)
+	}
+	keyID := secret.Annotations[EncryptionKeyIDAnnotation]
+	keys, err := s.getKeys(ctx)
+	if err != nil {
+		return err
+	}
+	key, found := keys.Data[keyID]
+	if !found {
+		return fmt.Errorf("key %s is not found in secret %s", keyID, s.keySecretRef)
+	}
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(secret.Annotations[EncryptedDataKeyAnnotation])
+	if err != nil {
+		return fmt.Errorf("cannot decode data key: %w", err)
+	}
+	dataKey, err := open(key, encryptedDataKey, []byte(keyID))
+	if err != nil {
+		return fmt.Errorf("cannot decrypt data key with key %s: %w", keyID, err)
+	}
+	data := make(map[string][]byte, len(secret.Data))
+	for key, value := range secret.Data {
+		if data[key], err = open(dataKey, value, valueAAD(secret, key)); err != nil {
+			return fmt.Errorf("cannot decrypt key %s: %w", key, err)
+		}
+	}
+	secret.Data = data
+	delete(secret.Annotations, PreviousFormatAnnotation)
+	delete(secret.Annotations, EncryptionKeyIDAnnotation)
+	delete(secret.Annotations, EncryptedDataKeyAnnotation)
+	return nil
+}
+
+func (s *EncryptedStore) getKeys(ctx context.Context) (*corev1.Secret, error) {
+	keys := &corev1.Secret{}
+	if err := s.client.Get(ctx, s.keySecretRef, keys); err != nil {
+		return nil, fmt.Errorf("cannot get key secret %s: %w", s.keySecretRef, err)
+	}
+	return keys, nil
+}
+
+func (s *EncryptedStore) getActiveKeyID(keys *corev1.Secret) (string, error) {
+	keyID := s.activeKeyID
+	if keyID == "" {
+		keyID = keys.Annotations[ActiveKeyIDAnnotation]
+	}
+	if keyID == "" {
+		if len(keys.Data) != 1 {
+			return "", fmt.Errorf("active key is not selected in secret %s", s.keySecretRef)
+		}
+		keyID = slices.Collect(maps.Keys(keys.Data))[0]
+	}
+	if _, found := keys.Data[keyID]; !found {
+		return "", fmt.Errorf("key %s is not found in secret %s", keyID, s.keySecretRef)
+	}
+	return keyID, nil
+}
+
+// valueAAD binds encrypted value to its key and "-old" secret, so values can't be swapped.
+func valueAAD(secret *corev1.Secret, key string) []byte {
+	return []byte(secret.Namespace + "/" + secret.Name + "/" + key)
+}
+
+// seal encrypts plaintext with AES-GCM, random nonce is prepended to the ciphertext.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,177 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testKeysRef = types.NamespacedName{Namespace: "test", Name: "encryption-keys"}
+
+func newKeySecret(activeKeyID string, keys map[string][]byte) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testKeysRef.Name, Namespace: testKeysRef.Namespace},
+		Data:       keys,
+	}
+	if activeKeyID != "" {
+		secret.Annotations = map[string]string{ActiveKeyIDAnnotation: activeKeyID}
+	}
+	return secret
+}
+
+func updateKeySecret(t *testing.T, k8sClient client.Client, activeKeyID string, keys map[string][]byte) {
+	t.Helper()
+	keySecret := &corev1.Secret{}
+	if err := k8sClient.Get(context.Background(), testKeysRef, keySecret); err != nil {
+		t.Fatalf("cannot get key secret: %v", err)
+	}
+	keySecret.Annotations = map[string]string{ActiveKeyIDAnnotation: activeKeyID}
+	keySecret.Data = keys
+	if err := k8sClient.Update(context.Background(), keySecret); err != nil {
+		t.Fatalf("cannot update key secret: %v", err)
+	}
+}
+
+func TestEncryptedStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewClientBuilder().
+		WithObjects(newKeySecret("", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})).
+		Build()
+	store := NewEncryptedStore(k8sClient, testKeysRef, "")
+	data := map[string][]byte{"username": []byte("admin"), "password": []byte("s3cr3t")}
+	saveCreds(t, store, data)
+
+	stored := getOldSecret(t, k8sClient)
+	if stored.Annotations[PreviousFormatAnnotation] != StoreEncrypted || stored.Annotations[EncryptionKeyIDAnnotation] != "k1" {
+		t.Fatalf("unexpected format annotations: %v", stored.Annotations)
+	}
+	for key, value := range data {
+		if bytes.Contains(stored.Data[key], value) {
+			t.Errorf("key %s is stored in plain form", key)
+		}
+	}
+
+	previous, err := store.Load(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("cannot load previous credentials: %v", err)
+	}
+	for key, value := range data {
+		if !bytes.Equal(previous.Data[key], value) {
+			t.Errorf("key %s is not decrypted", key)
+		}
+	}
+	if _, found := previous.Annotations[EncryptedDataKeyAnnotation]; found {
+		t.Errorf("format annotations are not removed from loaded secret")
+	}
+}
+
+func TestEncryptedStoreKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 16)
+	k8sClient := fake.NewClientBuilder().
+		WithObjects(newKeySecret("", map[string][]byte{"k1": oldKey})).
+		Build()
+	store := NewEncryptedStore(k8sClient, testKeysRef, "")
+	saveCreds(t, store, map[string][]byte{"password": []byte("s3cr3t")})
+
+	// New key is activated, the copy encrypted with the previous key is still decrypted
+	updateKeySecret(t, k8sClient, "k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	previous, err := store.Load(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("cannot load copy encrypted with previous key: %v", err)
+	}
+	if string(previous.Data["password"]) != "s3cr3t" {
+		t.Fatalf("copy encrypted with previous key is not decrypted")
+	}
+
+	if err = store.Reencrypt(ctx, testSecretRef); err != nil {
+		t.Fatalf("cannot re-encrypt copy: %v", err)
+	}
+	if keyID := getOldSecret(t, k8sClient).Annotations[EncryptionKeyIDAnnotation]; keyID != "k2" {
+		t.Fatalf("copy is encrypted with key %q after re-encryption, expected k2", keyID)
+	}
+
+	// Previous key is removed after re-encryption
+	updateKeySecret(t, k8sClient, "k2", map[string][]byte{"k2": newKey})
+	previous, err = store.Load(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("cannot load re-encrypted copy: %v", err)
+	}
+	if string(previous.Data["password"]) != "s3cr3t" {
+		t.Fatalf("re-encrypted copy is not decrypted")
+	}
+}
+
+func TestEncryptedStoreRejectsTamperedData(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(secret *corev1.Secret)
+	}{
+		{
+			name: "modified value",
+			tamper: func(secret *corev1.Secret) {
+				secret.Data["password"][len(secret.Data["password"])-1] ^= 1
+			},
+		},
+		{
+			name: "swapped values",
+			tamper: func(secret *corev1.Secret) {
+				secret.Data["password"], secret.Data["username"] = secret.Data["username"], secret.Data["password"]
+			},
+		},
+		{
+			name: "replaced key ID",
+			tamper: func(secret *corev1.Secret) {
+				secret.Annotations[EncryptionKeyIDAnnotation] = "k2"
+			},
+		},
+		{
+			name: "modified data key",
+			tamper: func(secret *corev1.Secret) {
+				secret.Annotations[EncryptedDataKeyAnnotation] = "AAAA" + secret.Annotations[EncryptedDataKeyAnnotation][4:]
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			// Both keys are equal, so only authentication of the key ID rejects the replaced ID
+			key := bytes.Repeat([]byte{1}, 32)
+			k8sClient := fake.NewClientBuilder().
+				WithObjects(newKeySecret("k1", map[string][]byte{"k1": key, "k2": key})).
+				Build()
+			store := NewEncryptedStore(k8sClient, testKeysRef, "")
+			saveCreds(t, store, map[string][]byte{"username": []byte("admin"), "password": []byte("s3cr3t")})
+
+			stored := getOldSecret(t, k8sClient)
+			test.tamper(stored)
+			if err := k8sClient.Update(ctx, stored); err != nil {
+				t.Fatalf("cannot update old secret: %v", err)
+			}
+			if _, err := store.Load(ctx, testSecretRef); err == nil {
+				t.Fatalf("tampered copy is loaded")
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var testSecretRef = types.NamespacedName{Namespace: "test", Name: "db-credentials"}
+
+func newTestSecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testSecretRef.Namespace},
+		Data:       make(map[string][]byte, len(data)),
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func getTestSecret(t *testing.T, k8sClient client.Client, name string) *corev1.Secret {
+	t.Helper()
+	secret := &corev1.Secret{}
+	secretRef := types.NamespacedName{Namespace: testSecretRef.Namespace, Name: name}
+	if err := k8sClient.Get(context.Background(), secretRef, secret); err != nil {
+		t.Fatalf("cannot get %s secret: %v", name, err)
+	}
+	return secret
+}
+
+func getOldSecret(t *testing.T, k8sClient client.Client) *corev1.Secret {
+	t.Helper()
+	return getTestSecret(t, k8sClient, testSecretRef.Name+"-old")
+}
+
+func saveCreds(t *testing.T, store PreviousCredsStore, data map[string][]byte) {
+	t.Helper()
+	err := store.Save(context.Background(), testSecretRef, func(previous *corev1.Secret) {
+		previous.Data = data
+	})
+	if err != nil {
+		t.Fatalf("cannot save previous credentials: %v", err)
+	}
+}
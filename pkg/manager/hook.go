@@ -0,0 +1,182 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PrepareOldCreds creates "-old" copies of the provided secrets and locks them.
+// Processing continues when a secret fails, the returned error aggregates all failures.
+func (m *CredentialManager) PrepareOldCreds(ctx context.Context, secretRefs []types.NamespacedName) error {
+	var errs []error
+	for _, secretRef := range secretRefs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := m.prepareOldCreds(ctx, secretRef); err != nil {
+			logger.Error(fmt.Sprintf("cannot prepare old credentials for %s secret", secretRef), zap.Error(err))
+			m.recorder.Warning(secretRef, recorder.ReasonPrepareFailed, "Cannot prepare old credentials: %v", err)
+			errs = append(errs, fmt.Errorf("secret %s: %w", secretRef, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *CredentialManager) prepareOldCreds(ctx context.Context, secretRef types.NamespacedName) error {
+	oldSecretRef := utils.GetOldSecretRef(secretRef)
+	logger.Info(fmt.Sprintf("Creation of secret %s was started", oldSecretRef))
+
+	newSecret := &corev1.Secret{}
+	err := m.client.Get(ctx, secretRef, newSecret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info(fmt.Sprintf("secret %s is not found, skipping...", secretRef))
+			return nil
+		}
+		return fmt.Errorf("cannot get %s secret: %w", secretRef, err)
+	}
+	now := time.Now()
+	if lock.IsLocked(newSecret, now) {
+		logger.Info("Secret is locked, skip old secret update...")
+		return nil
+	}
+
+	// Invalid credentials fail the hook before the secret is locked, so upgrade is stopped early
+	if err = m.validateSecret(ctx, secretRef, newSecret); err != nil {
+		return err
+	}
+
+	if record := lock.Get(newSecret); record != nil {
+		// Expired lock means credentials were not actualized, so existing copy still contains applied credentials
+		logger.Info(fmt.Sprintf("Lock of secret %s held by %s is expired, the lock will be taken over", secretRef, record.Holder))
+		if record.AcquiredAt.IsZero() {
+			m.recorder.Warning(secretRef, recorder.ReasonLockExpired, "Lock without acquisition time is treated as expired, the lock is taken over by %s",
+				m.lockHolder)
+		} else {
+			m.recorder.Warning(secretRef, recorder.ReasonLockExpired, "Lock held by %s since %s is expired, the lock is taken over by %s",
+				record.Holder, record.AcquiredAt.Format(time.RFC3339), m.lockHolder)
+		}
+		if _, err = m.store.Load(ctx, secretRef); apierrors.IsNotFound(err) {
+			if err = m.saveSecretCopy(ctx, newSecret); err != nil {
+				return fmt.Errorf("cannot save %s secret: %w", oldSecretRef, err)
+			}
+		} else if err != nil {
+			return err
+		}
+	} else if err = m.saveSecretCopy(ctx, newSecret); err != nil {
+		return fmt.Errorf("cannot save %s secret: %w", oldSecretRef, err)
+	}
+
+	err = m.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+		if record := lock.Get(secret); record != nil && !record.IsExpired(now) {
+			return fmt.Errorf("secret is locked by %s", record.Holder)
+		}
+		lock.Acquire(secret, m.lockHolder, m.lockTTL, now)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot lock %s secret: %w", secretRef, err)
+	}
+	m.recorder.Normal(secretRef, recorder.ReasonLockAcquired, "Credentials secret locked by %s for %s", m.lockHolder, m.lockTTL)
+	return nil
+}
+
+// IsSecretExist checks if the secret is present.
+func (m *CredentialManager) IsSecretExist(ctx context.Context, secretRef types.NamespacedName) (bool, error) {
+	newSecret := &corev1.Secret{}
+	err := m.client.Get(ctx, secretRef, newSecret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		logger.Info(fmt.Sprintf("cannot get %s secret", secretRef))
+		return false, err
+	}
+	return true, nil
+}
+
+// ClearHooks deletes Job and Pod objects with the hook name prefix in the manager namespace.
+func (m *CredentialManager) ClearHooks(ctx context.Context) error {
+	hookObjects, err := m.getHookObjects(ctx)
+	if err != nil {
+		return err
+	}
+	for _, hookObject := range hookObjects {
+		err = m.client.Delete(ctx, hookObject)
+		if err != nil {
+			logger.Error(fmt.Sprintf("cannot delete hook object %s", hookObject.GetName()), zap.Error(err))
+			return err
+		}
+		logger.Info(fmt.Sprintf("credential hook object %s has been deleted", hookObject.GetName()))
+	}
+	return nil
+}
+
+func (m *CredentialManager) getHookObjects(ctx context.Context) ([]client.Object, error) {
+	resultList := make([]client.Object, 0)
+	jobObjects, err := m.getJobsAndPods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	credHookName := m.hookName
+	for _, credHook := range jobObjects {
+		if strings.HasPrefix(credHook.GetName(), credHookName) {
+			resultList = append(resultList, credHook)
+		}
+	}
+
+	return resultList, nil
+}
+
+func (m *CredentialManager) getJobsAndPods(ctx context.Context) ([]client.Object, error) {
+	objects := make([]client.Object, 0)
+	opts := []client.ListOption{
+		client.InNamespace(m.namespace),
+	}
+	jobList := &batchv1.JobList{}
+	if err := m.client.List(ctx, jobList, opts...); err != nil {
+		logger.Error("cannot get Job list", zap.Error(err))
+		return nil, err
+	}
+	for _, job := range jobList.Items {
+		objects = append(objects, &job)
+	}
+
+	podList := &corev1.PodList{}
+	if err := m.client.List(ctx, podList, opts...); err != nil {
+		logger.Error("cannot get Pod list", zap.Error(err))
+		return nil, err
+	}
+	for _, pod := range podList.Items {
+		objects = append(objects, &pod)
+	}
+	return objects, nil
+}
@@ -18,148 +18,516 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
-
+	"maps"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/Netcracker/qubership-credential-manager/pkg/informer"
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/password"
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
 	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const (
-	lockLabel = "locked-for-watcher"
-)
-
 var (
-	namespace = utils.GetNamespace()
-	logger    = utils.GetLogger()
+	logger = utils.GetLogger()
 
-	k8sClientInstance client.Client
-	once              sync.Once
+	defaultManager *CredentialManager
+	once           sync.Once
 )
 
-func GetK8SClient() client.Client {
+// CredentialManager performs credentials operations with the provided clients.
+// Secrets may be located in any namespace, namespace is used for secrets provided by name only.
+type CredentialManager struct {
+	client    client.Client
+	clientSet kubernetes.Interface
+	namespace string
+	informer  *informer.Informer
+
+	lockHolder  string
+	lockTTL     time.Duration
+	diffOptions utils.DiffOptions
+	store       PreviousCredsStore
+	hookName    string
+	validators  []Validator
+
+	eventRecorder record.EventRecorder
+	eventOwner    runtime.Object
+	recorder      *recorder.Recorder
+
+	informerOptions []informer.Option
+}
+
+// Option configures CredentialManager.
+type Option func(m *CredentialManager)
+
+// WithLockHolder sets identity stored in the lock of the secrets. By default lock.DefaultHolder is used.
+func WithLockHolder(holder string) Option {
+	return func(m *CredentialManager) {
+		m.lockHolder = holder
+	}
+}
+
+// WithLockTTL sets time after which the lock of the secrets is treated as expired. By default lock.GetTTL is used.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(m *CredentialManager) {
+		m.lockTTL = ttl
+	}
+}
+
+// WithDiffOptions configures which parts of the secrets are compared to detect credentials change.
+// Selected labels and annotations are also stored in "-old" copies.
+func WithDiffOptions(diffOptions utils.DiffOptions) Option {
+	return func(m *CredentialManager) {
+		m.diffOptions = diffOptions
+	}
+}
+
+// WithPreviousCredsStore sets store of the previous credentials. By default SecretStore is used.
+func WithPreviousCredsStore(store PreviousCredsStore) Option {
+	return func(m *CredentialManager) {
+		m.store = store
+	}
+}
+
+// WithHookName sets prefix of the hook Job and Pod objects deleted by ClearHooks. By default utils.GetHookName is used.
+func WithHookName(hookName string) Option {
+	return func(m *CredentialManager) {
+		m.hookName = hookName
+	}
+}
+
+// WithEventRecorder enables Events posting on credentials secrets for each credentials lifecycle step.
+func WithEventRecorder(eventRecorder record.EventRecorder) Option {
+	return func(m *CredentialManager) {
+		m.eventRecorder = eventRecorder
+	}
+}
+
+// WithEventOwner enables Events duplication on the owner object, e.g. custom resource of the operator.
+// Events are posted only if event recorder is configured.
+func WithEventOwner(owner runtime.Object) Option {
+	return func(m *CredentialManager) {
+		m.eventOwner = owner
+	}
+}
+
+// WithInformerOptions sets options of the informer used by Watch methods, e.g. informer.WithDebounce.
+func WithInformerOptions(opts ...informer.Option) Option {
+	return func(m *CredentialManager) {
+		m.informerOptions = append(m.informerOptions, opts...)
+	}
+}
+
+// NewCredentialManager creates CredentialManager which works with provided clients, namespace is used as default one.
+func NewCredentialManager(k8sClient client.Client, clientSet kubernetes.Interface, namespace string, opts ...Option) *CredentialManager {
+	m := &CredentialManager{
+		client:     k8sClient,
+		clientSet:  clientSet,
+		namespace:  namespace,
+		lockHolder: lock.DefaultHolder(),
+		lockTTL:    lock.GetTTL(),
+		hookName:   utils.GetHookName(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.store == nil {
+		m.store = NewSecretStore(k8sClient)
+	}
+	m.recorder = recorder.New(m.eventRecorder, m.eventOwner)
+	informerOptions := append([]informer.Option{informer.WithDiffOptions(m.diffOptions), informer.WithRecorder(m.recorder)},
+		m.informerOptions...)
+	m.informer = informer.NewInformer(k8sClient, clientSet, namespace, informerOptions...)
+	return m
+}
+
+// NewCredentialManagerFromEnv creates CredentialManager with the previous credentials store and validation rules
+// configured by the environment variables. Configuration errors are returned, provided options are applied last.
+func NewCredentialManagerFromEnv(k8sClient client.Client, clientSet kubernetes.Interface, namespace string, opts ...Option) (*CredentialManager, error) {
+	store, err := NewPreviousCredsStore(utils.GetEnv("PREVIOUS_CREDS_STORE", StoreSecret), k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := GetValidationRules()
+	if err != nil {
+		return nil, err
+	}
+	envOpts := []Option{WithPreviousCredsStore(store)}
+	if !rules.IsEmpty() {
+		envOpts = append(envOpts, WithValidators(rules))
+	}
+	return NewCredentialManager(k8sClient, clientSet, namespace, append(envOpts, opts...)...), nil
+}
+
+// Default returns CredentialManager built from the environment clients and namespace.
+// It panics if the environment configuration is invalid.
+func Default() *CredentialManager {
 	once.Do(func() {
-		k8sClientInstance = utils.GetK8SClient()
+		var err error
+		defaultManager, err = NewCredentialManagerFromEnv(utils.GetK8SClient(), utils.GetClientSet(), utils.GetNamespace())
+		if err != nil {
+			panic(err)
+		}
 	})
-	return k8sClientInstance
+	return defaultManager
+}
+
+func GetK8SClient() client.Client {
+	return Default().client
+}
+
+// Client returns controller-runtime client used by the manager.
+func (m *CredentialManager) Client() client.Client {
+	return m.client
+}
+
+// ClientSet returns Kubernetes clientset used by the manager.
+func (m *CredentialManager) ClientSet() kubernetes.Interface {
+	return m.clientSet
+}
+
+// Namespace returns default namespace of the manager.
+func (m *CredentialManager) Namespace() string {
+	return m.namespace
+}
+
+// PreviousCredsStore returns store of the previous credentials used by the manager.
+func (m *CredentialManager) PreviousCredsStore() PreviousCredsStore {
+	return m.store
+}
+
+// DiffOptions returns options used to detect credentials change.
+func (m *CredentialManager) DiffOptions() utils.DiffOptions {
+	return m.diffOptions
 }
 
 func AreCredsChanged(secretNames []string) (bool, error) {
-	for _, secretName := range secretNames {
-		newSecret, err := getSecret(secretName)
-		if err != nil {
-			return false, err
-		}
-		oldSecretName := utils.GetOldSecretName(secretName)
-		oldSecret, err := getSecret(oldSecretName)
+	m := Default()
+	return m.AreCredsChanged(context.Background(), m.SecretRefs(secretNames))
+}
+
+func (m *CredentialManager) AreCredsChanged(ctx context.Context, secretRefs []types.NamespacedName) (bool, error) {
+	for _, secretRef := range secretRefs {
+		diff, err := m.DiffCreds(ctx, secretRef)
 		if err != nil {
 			return false, err
 		}
-		if utils.AreFieldsChanged(oldSecret, newSecret) {
+		if diff.HasChanges() {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
-func ActualizeCreds(secretName string, changeCredsFunc func(newSecret, oldSecret *corev1.Secret) error) (err error) {
+// DiffCreds returns keys changed in the secret since the previous credentials were saved.
+func DiffCreds(secretName string) (utils.SecretDiff, error) {
+	m := Default()
+	return m.DiffCreds(context.Background(), m.SecretRef(secretName))
+}
+
+// DiffCreds returns keys changed in the secret since the previous credentials were saved.
+func (m *CredentialManager) DiffCreds(ctx context.Context, secretRef types.NamespacedName) (utils.SecretDiff, error) {
+	newSecret, err := m.getSecret(ctx, secretRef)
+	if err != nil {
+		return utils.SecretDiff{}, err
+	}
+	oldSecret, err := m.store.Load(ctx, secretRef)
+	if err != nil {
+		return utils.SecretDiff{}, err
+	}
+	return utils.DiffSecrets(oldSecret, m.store.Comparable(oldSecret, newSecret), m.diffOptions), nil
+}
+
+// SetCreds writes new values of the keys into the secret, so the change is processed by informer and ActualizeCreds.
+func SetCreds(secretName string, values map[string][]byte) error {
+	m := Default()
+	return m.SetCreds(context.Background(), m.SecretRef(secretName), values)
+}
+
+// SetCreds writes new values of the keys into the secret, so the change is processed by informer and ActualizeCreds.
+func (m *CredentialManager) SetCreds(ctx context.Context, secretRef types.NamespacedName, values map[string][]byte) error {
+	err := m.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte, len(values))
+		}
+		for key, value := range values {
+			secret.Data[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("New credentials were written into secret %s", secretRef), zap.Strings("keys", slices.Sorted(maps.Keys(values))))
+	return nil
+}
+
+// Rotate generates new values of the keys according to the policy and writes them into the secret.
+func Rotate(secretName string, keys []string, policy password.Policy) error {
+	m := Default()
+	return m.Rotate(context.Background(), m.SecretRef(secretName), keys, policy)
+}
+
+// Rotate generates new values of the keys according to the policy and writes them into the secret,
+// so the change is processed by informer and ActualizeCreds.
+func (m *CredentialManager) Rotate(ctx context.Context, secretRef types.NamespacedName, keys []string, policy password.Policy) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys to rotate are provided")
+	}
+	values, err := password.GenerateValues(keys, policy)
+	if err != nil {
+		return err
+	}
+	if err = m.SetCreds(ctx, secretRef, values); err != nil {
+		return err
+	}
+	m.recorder.Normal(secretRef, recorder.ReasonGenerated, "New credentials generated for keys: %v", keys)
+	return nil
+}
+
+func ActualizeCreds(secretName string, changeCredsFunc func(newSecret, oldSecret *corev1.Secret) error) error {
+	m := Default()
+	return m.ActualizeCreds(context.Background(), m.SecretRef(secretName), changeCredsFunc)
+}
+
+func (m *CredentialManager) ActualizeCreds(ctx context.Context, secretRef types.NamespacedName, changeCredsFunc func(newSecret, oldSecret *corev1.Secret) error) error {
+	return m.ActualizeCredsDiff(ctx, secretRef, func(diff *CredsDiff) error {
+		return changeCredsFunc(diff.NewSecret, diff.OldSecret)
+	})
+}
+
+// ActualizeCredsDiff is the same as ActualizeCreds, but changeCredsFunc receives diff with the changed keys,
+// so only affected credentials can be rotated.
+func ActualizeCredsDiff(secretName string, changeCredsFunc func(diff *CredsDiff) error) error {
+	m := Default()
+	return m.ActualizeCredsDiff(context.Background(), m.SecretRef(secretName), changeCredsFunc)
+}
+
+// ActualizeCredsDiff is the same as ActualizeCreds, but changeCredsFunc receives diff with the changed keys,
+// so only affected credentials can be rotated.
+func (m *CredentialManager) ActualizeCredsDiff(ctx context.Context, secretRef types.NamespacedName, changeCredsFunc func(diff *CredsDiff) error) error {
+	return m.ActualizeCredsTransactional(ctx, secretRef, func(diff *CredsDiff, _ *Progress) error {
+		return changeCredsFunc(diff)
+	})
+}
+
+// ActualizeCredsTransactional is the same as ActualizeCredsDiff, but changeCredsFunc reports applied keys with Progress.
+// Applied keys are stored in "-old" copy right away, so if changeCredsFunc fails, the next call receives only remaining keys.
+func ActualizeCredsTransactional(secretName string, changeCredsFunc func(diff *CredsDiff, progress *Progress) error) error {
+	m := Default()
+	return m.ActualizeCredsTransactional(context.Background(), m.SecretRef(secretName), changeCredsFunc)
+}
+
+// ActualizeCredsTransactional is the same as ActualizeCredsDiff, but changeCredsFunc reports applied keys with Progress.
+// Applied keys are stored in "-old" copy right away, so if changeCredsFunc fails, the next call receives only remaining keys.
+func (m *CredentialManager) ActualizeCredsTransactional(ctx context.Context, secretRef types.NamespacedName, changeCredsFunc func(diff *CredsDiff, progress *Progress) error) (err error) {
+	var rotatedAt time.Time
 	defer func() {
 		if err == nil {
-			err = unlockSecret(secretName)
+			err = m.unlockSecret(ctx, secretRef, rotatedAt)
 			if err != nil {
 				logger.Error("Credentials secret wasn't unlocked", zap.Error(err))
+				m.recorder.Warning(secretRef, recorder.ReasonUnlockFailed, "Credentials secret wasn't unlocked: %v", err)
 			}
 		}
 	}()
 
-	newSecret, err := getSecret(secretName)
+	newSecret, err := m.getSecret(ctx, secretRef)
 	if err != nil {
 		return
 	}
-	oldSecretName := utils.GetOldSecretName(secretName)
-	oldSecret, err := getSecret(oldSecretName)
+	oldSecret, err := m.store.Load(ctx, secretRef)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			oldSecret := getNewSecret(oldSecretName)
-			oldSecret.Data = newSecret.Data
-			oldSecret.Labels = newSecret.Labels
-			err = createSecret(oldSecret)
+			if err = m.validateCreds(ctx, m.initialCredsDiff(secretRef, newSecret)); err != nil {
+				return
+			}
+			err = m.saveSecretCopy(ctx, newSecret)
 			return
 		}
 		return
 	}
 
-	if !utils.AreFieldsChanged(oldSecret, newSecret) {
+	credsDiff := m.newCredsDiff(oldSecret, newSecret)
+	diff := credsDiff.SecretDiff
+	if !diff.HasChanges() {
+		return
+	}
+	if err = m.validateCreds(ctx, credsDiff); err != nil {
 		return
 	}
+	logger.Info(fmt.Sprintf("Credentials of secret %s were changed", secretRef),
+		zap.Strings("addedKeys", diff.AddedKeys),
+		zap.Strings("removedKeys", diff.RemovedKeys),
+		zap.Strings("changedKeys", diff.ChangedKeys))
 
-	err = changeCredsFunc(newSecret, oldSecret)
+	progress := m.newProgress(ctx, secretRef, newSecret, oldSecret)
+	rotationAttempts.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+	m.recorder.Normal(secretRef, recorder.ReasonRotationStarted, "Credentials rotation started, added keys: %v, removed keys: %v, changed keys: %v",
+		diff.AddedKeys, diff.RemovedKeys, diff.ChangedKeys)
+	defer func() {
+		if err != nil {
+			rotationFailures.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+			m.recorder.Warning(secretRef, recorder.ReasonRotationFailed, "Credentials rotation failed: %v", err)
+		} else {
+			rotationSuccesses.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+			m.recorder.Normal(secretRef, recorder.ReasonRotationSucceeded, "Credentials rotation succeeded")
+		}
+	}()
+	startTime := time.Now()
+	err = changeCredsFunc(credsDiff, progress)
+	changeCredsDuration.WithLabelValues(secretRef.Namespace, secretRef.Name).Observe(time.Since(startTime).Seconds())
 	if err != nil {
+		if appliedKeys := progress.AppliedKeys(); len(appliedKeys) > 0 {
+			logger.Error(fmt.Sprintf("Credentials of secret %s were applied partially", secretRef),
+				zap.Strings("appliedKeys", appliedKeys), zap.Error(err))
+		}
 		return
 	}
 
-	oldSecret.Data = newSecret.Data
-	err = updateSecret(oldSecret)
+	err = m.saveSecretCopy(ctx, newSecret)
+	if err == nil {
+		rotatedAt = time.Now()
+	}
 	return
 }
 
-func getNewSecret(secretName string) *corev1.Secret {
+func newOpaqueSecret(secretRef types.NamespacedName) *corev1.Secret {
 	return &corev1.Secret{
 		Type: corev1.SecretTypeOpaque,
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      secretName,
-			Namespace: namespace,
+			Name:      secretRef.Name,
+			Namespace: secretRef.Namespace,
 		},
 	}
 }
 
-func unlockSecret(secretName string) error {
-	logger.Info("Secret will be unlocked")
-	secret, err := getSecret(secretName)
+// unlockSecret releases lock of the secret, rotation time is stamped if it is not zero.
+func (m *CredentialManager) unlockSecret(ctx context.Context, secretRef types.NamespacedName, rotatedAt time.Time) error {
+	logger.Info(fmt.Sprintf("Secret %s will be unlocked", secretRef))
+	err := m.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+		lock.Release(secret)
+		if !rotatedAt.IsZero() {
+			setLastRotated(secret, rotatedAt)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	if secret.Annotations == nil {
-		secret.Annotations = make(map[string]string)
+	m.recorder.Normal(secretRef, recorder.ReasonUnlocked, "Credentials secret unlocked")
+	return nil
+}
+
+// ForceUnlock releases locks of the secrets regardless of lock holder and expiration.
+func ForceUnlock(secretNames []string) error {
+	m := Default()
+	return m.ForceUnlock(context.Background(), m.SecretRefs(secretNames))
+}
+
+// ForceUnlock releases locks of the secrets regardless of lock holder and expiration.
+func (m *CredentialManager) ForceUnlock(ctx context.Context, secretRefs []types.NamespacedName) error {
+	var errs []error
+	for _, secretRef := range secretRefs {
+		err := m.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+			if record := lock.Get(secret); record != nil {
+				logger.Info(fmt.Sprintf("Lock of secret %s held by %s will be released", secretRef, record.Holder))
+			}
+			lock.Release(secret)
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", secretRef, err))
+			continue
+		}
+		m.recorder.Normal(secretRef, recorder.ReasonForceUnlocked, "Credentials secret lock was forcibly released")
 	}
-	secret.Annotations[lockLabel] = "false"
-	return GetK8SClient().Update(context.Background(), secret)
+	return stderrors.Join(errs...)
 }
 
-func createSecret(secret *corev1.Secret) error {
-	err := GetK8SClient().Create(context.TODO(), secret)
+// saveSecretCopy saves data, labels and compared annotations of the secret in the previous credentials store.
+// Other fields of the existing copy, e.g. owner references, are preserved.
+func (m *CredentialManager) saveSecretCopy(ctx context.Context, secret *corev1.Secret) error {
+	secretRef := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+	oldSecretRef := utils.GetOldSecretRef(secretRef)
+	err := m.store.Save(ctx, secretRef, func(oldSecret *corev1.Secret) {
+		m.copySecretFields(secret, oldSecret)
+	})
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to create secret %v", secret.ObjectMeta.Name), zap.Error(err))
+		logger.Error(fmt.Sprintf("Failed to save secret %v", oldSecretRef), zap.Error(err))
 		return err
 	}
+	m.recorder.Normal(secretRef, recorder.ReasonOldCopySaved,
+		"Credentials copy %s saved", oldSecretRef.Name)
 	return nil
 }
 
-func updateSecret(secret *corev1.Secret) error {
-	err := GetK8SClient().Update(context.TODO(), secret)
+func (m *CredentialManager) copySecretFields(from, to *corev1.Secret) {
+	to.Data = from.Data
+	to.Labels = from.Labels
+	delete(to.Annotations, AppliedKeysAnnotation)
+	for _, annotation := range m.diffOptions.Annotations {
+		value, found := from.Annotations[annotation]
+		if !found {
+			delete(to.Annotations, annotation)
+			continue
+		}
+		if to.Annotations == nil {
+			to.Annotations = make(map[string]string)
+		}
+		to.Annotations[annotation] = value
+	}
+}
+
+// updateSecret reads the secret, applies mutate function and updates it.
+// On conflict the secret is re-read and the change is reapplied.
+func (m *CredentialManager) updateSecret(ctx context.Context, secretRef types.NamespacedName, mutate func(secret *corev1.Secret) error) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret := &corev1.Secret{}
+		if err := m.client.Get(ctx, secretRef, secret); err != nil {
+			return err
+		}
+		if err := mutate(secret); err != nil {
+			return err
+		}
+		return m.client.Update(ctx, secret)
+	})
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to update secret %v", secret.ObjectMeta.Name), zap.Error(err))
+		logger.Error(fmt.Sprintf("Failed to update secret %v", secretRef), zap.Error(err))
 		return err
 	}
 	return nil
 }
 
 func SetOwnerRefForSecretCopies(secretNames []string, ownerRef []metav1.OwnerReference) error {
-	for _, secretName := range secretNames {
-		oldSecretName := utils.GetOldSecretName(secretName)
-		secret, err := getSecret(oldSecretName)
-		if err != nil {
-			return err
-		}
-		secret.OwnerReferences = ownerRef
-		err = updateSecret(secret)
+	m := Default()
+	return m.SetOwnerRefForSecretCopies(context.Background(), m.SecretRefs(secretNames), ownerRef)
+}
+
+// SetOwnerRefForSecretCopies sets owner references for "-old" secret copies.
+// Kubernetes doesn't allow cross-namespace owners, so owner must be located in the secret namespace.
+func (m *CredentialManager) SetOwnerRefForSecretCopies(ctx context.Context, secretRefs []types.NamespacedName, ownerRef []metav1.OwnerReference) error {
+	for _, secretRef := range secretRefs {
+		err := m.store.Update(ctx, secretRef, func(secret *corev1.Secret) error {
+			secret.OwnerReferences = ownerRef
+			return nil
+		})
 		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to set owner references of secret %v", utils.GetOldSecretRef(secretRef)), zap.Error(err))
 			return err
 		}
 	}
@@ -167,8 +535,13 @@ func SetOwnerRefForSecretCopies(secretNames []string, ownerRef []metav1.OwnerRef
 }
 
 func AddCredHashToPodTemplate(secretNames []string, template *corev1.PodTemplateSpec) error {
-	for i, secretName := range secretNames {
-		patroniHash, err := CalculateSecretDataHash(secretName)
+	m := Default()
+	return m.AddCredHashToPodTemplate(context.Background(), m.SecretRefs(secretNames), template)
+}
+
+func (m *CredentialManager) AddCredHashToPodTemplate(ctx context.Context, secretRefs []types.NamespacedName, template *corev1.PodTemplateSpec) error {
+	for i, secretRef := range secretRefs {
+		patroniHash, err := m.CalculateSecretDataHash(ctx, secretRef)
 		if err != nil {
 			return err
 		}
@@ -195,20 +568,73 @@ func AddAnnotationsToPodTemplate(template *corev1.PodTemplateSpec, annotations m
 }
 
 func CalculateSecretDataHash(secretName string) (string, error) {
-	secret, err := getSecret(secretName)
+	m := Default()
+	return m.CalculateSecretDataHash(context.Background(), m.SecretRef(secretName))
+}
+
+func (m *CredentialManager) CalculateSecretDataHash(ctx context.Context, secretRef types.NamespacedName) (string, error) {
+	secret, err := m.getSecret(ctx, secretRef)
 	if err != nil {
 		return "", err
 	}
 	return hash(secret.Data)
 }
 
-func getSecret(secretName string) (*corev1.Secret, error) {
+// Watch starts watchers for the provided secrets, reconcileFunc is triggered on credentials change.
+func (m *CredentialManager) Watch(secretRefs []types.NamespacedName, reconcileFunc func()) error {
+	return m.informer.Watch(secretRefs, reconcileFunc)
+}
+
+// WatchEvents starts watchers for the provided secrets, handler receives event on each credentials change.
+func (m *CredentialManager) WatchEvents(secretRefs []types.NamespacedName, handler informer.EventHandler) error {
+	return m.informer.WatchEvents(secretRefs, handler)
+}
+
+// WatchRetryable starts watchers for the provided secrets, failed handler calls are retried with backoff.
+func (m *CredentialManager) WatchRetryable(ctx context.Context, secretRefs []types.NamespacedName, handler informer.RetryableEventHandler) (*informer.WatchHandle, error) {
+	return m.informer.WatchRetryable(ctx, secretRefs, handler)
+}
+
+// WatchRunnable creates controller-runtime runnable which runs watchers of the provided secrets on the elected leader only.
+func (m *CredentialManager) WatchRunnable(secretRefs []types.NamespacedName, handler informer.RetryableEventHandler) *informer.Runnable {
+	return m.informer.Runnable(secretRefs, handler)
+}
+
+// RunWithLease runs watchers of the provided secrets while Lease is held and blocks until the context is cancelled.
+func (m *CredentialManager) RunWithLease(ctx context.Context, config informer.LeaseConfig, secretRefs []types.NamespacedName, handler informer.RetryableEventHandler) error {
+	return m.informer.RunWithLease(ctx, config, secretRefs, handler)
+}
+
+// WatchContext starts watchers for the provided secrets which are stopped on context cancellation.
+func (m *CredentialManager) WatchContext(ctx context.Context, secretRefs []types.NamespacedName, reconcileFunc func()) (*informer.WatchHandle, error) {
+	return m.informer.WatchContext(ctx, secretRefs, reconcileFunc)
+}
+
+// WatchEventsContext starts watchers for the provided secrets which are stopped on context cancellation.
+func (m *CredentialManager) WatchEventsContext(ctx context.Context, secretRefs []types.NamespacedName, handler informer.EventHandler) (*informer.WatchHandle, error) {
+	return m.informer.WatchEventsContext(ctx, secretRefs, handler)
+}
+
+// Unwatch stops watchers of the provided secrets and waits until they are finished.
+func (m *CredentialManager) Unwatch(secretRefs []types.NamespacedName) {
+	m.informer.Unwatch(secretRefs)
+}
+
+// SecretRef converts secret name in "namespace/name" or "name" form to reference using manager namespace as default.
+func (m *CredentialManager) SecretRef(secretName string) types.NamespacedName {
+	return utils.GetSecretRef(secretName, m.namespace)
+}
+
+// SecretRefs converts secret names in "namespace/name" or "name" form to references using manager namespace as default.
+func (m *CredentialManager) SecretRefs(secretNames []string) []types.NamespacedName {
+	return utils.GetSecretRefs(secretNames, m.namespace)
+}
+
+func (m *CredentialManager) getSecret(ctx context.Context, secretRef types.NamespacedName) (*corev1.Secret, error) {
 	foundSecret := &corev1.Secret{}
-	err := GetK8SClient().Get(context.TODO(), types.NamespacedName{
-		Name: secretName, Namespace: namespace,
-	}, foundSecret)
+	err := m.client.Get(ctx, secretRef, foundSecret)
 	if err != nil {
-		logger.Error(fmt.Sprintf("can't find the secret %s", secretName), zap.Error(err))
+		logger.Error(fmt.Sprintf("can't find the secret %s", secretRef), zap.Error(err))
 		return foundSecret, err
 	}
 	return foundSecret, nil
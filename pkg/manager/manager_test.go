@@ -0,0 +1,166 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestManager(objects ...client.Object) (*CredentialManager, client.Client) {
+	k8sClient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	return NewCredentialManager(k8sClient, k8sfake.NewClientset(), testSecretRef.Namespace), k8sClient
+}
+
+func updateTestSecret(t *testing.T, k8sClient client.Client, name string, data map[string]string) {
+	t.Helper()
+	secret := getTestSecret(t, k8sClient, name)
+	secret.Data = newTestSecret(name, data).Data
+	if err := k8sClient.Update(context.Background(), secret); err != nil {
+		t.Fatalf("cannot update %s secret: %v", name, err)
+	}
+}
+
+func TestNewCredentialManager(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().Build()
+	clientSet := k8sfake.NewClientset()
+	m := NewCredentialManager(k8sClient, clientSet, "test")
+	if m.Client() != k8sClient || m.ClientSet() != clientSet || m.Namespace() != "test" {
+		t.Fatalf("manager must use the provided clients and namespace")
+	}
+}
+
+func TestPrepareOldCreds(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}))
+
+	if err := m.PrepareOldCreds(ctx, []types.NamespacedName{testSecretRef, {Namespace: testSecretRef.Namespace, Name: "missing"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value := string(getOldSecret(t, k8sClient).Data["password"]); value != "admin" {
+		t.Fatalf("copy must contain current credentials, got %q", value)
+	}
+	if !lock.IsLocked(getTestSecret(t, k8sClient, testSecretRef.Name), time.Now()) {
+		t.Fatalf("secret must be locked")
+	}
+
+	// The copy of locked secret is not refreshed
+	updateTestSecret(t, k8sClient, testSecretRef.Name, map[string]string{"password": "new-admin"})
+	if err := m.PrepareOldCreds(ctx, []types.NamespacedName{testSecretRef}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value := string(getOldSecret(t, k8sClient).Data["password"]); value != "admin" {
+		t.Fatalf("copy of locked secret must not be updated, got %q", value)
+	}
+}
+
+func TestPrepareOldCredsCanceledContext(t *testing.T) {
+	m, _ := newTestManager(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.PrepareOldCreds(ctx, []types.NamespacedName{testSecretRef}); err == nil {
+		t.Fatalf("error is expected for canceled context")
+	}
+}
+
+func TestActualizeCreds(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}))
+	calls := 0
+	changeCreds := func(newSecret, oldSecret *corev1.Secret) error {
+		calls++
+		if string(oldSecret.Data["password"]) != "admin" || string(newSecret.Data["password"]) != "new-admin" {
+			t.Errorf("unexpected credentials passed: old %q, new %q", oldSecret.Data["password"], newSecret.Data["password"])
+		}
+		return nil
+	}
+
+	// The first call only creates the copy
+	if err := m.ActualizeCreds(ctx, testSecretRef, changeCreds); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("credentials must not be changed when copy is absent")
+	}
+	changed, err := m.AreCredsChanged(ctx, []types.NamespacedName{testSecretRef})
+	if err != nil || changed {
+		t.Fatalf("credentials must not be changed after copy creation, changed %v, error %v", changed, err)
+	}
+
+	updateTestSecret(t, k8sClient, testSecretRef.Name, map[string]string{"password": "new-admin"})
+	changed, err = m.AreCredsChanged(ctx, []types.NamespacedName{testSecretRef})
+	if err != nil || !changed {
+		t.Fatalf("credentials must be changed, changed %v, error %v", changed, err)
+	}
+	if err = m.ActualizeCreds(ctx, testSecretRef, changeCreds); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("credentials must be changed once, got %d calls", calls)
+	}
+	if value := string(getOldSecret(t, k8sClient).Data["password"]); value != "new-admin" {
+		t.Fatalf("copy must contain new credentials, got %q", value)
+	}
+	if lock.IsLocked(getTestSecret(t, k8sClient, testSecretRef.Name), time.Now()) {
+		t.Fatalf("secret must be unlocked")
+	}
+}
+
+func TestSetOwnerRefForSecretCopies(t *testing.T) {
+	m, k8sClient := newTestManager(newTestSecret(utils.GetOldSecretName(testSecretRef.Name), map[string]string{"password": "admin"}))
+	ownerRef := []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "uid"}}
+	if err := m.SetOwnerRefForSecretCopies(context.Background(), []types.NamespacedName{testSecretRef}, ownerRef); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refs := getOldSecret(t, k8sClient).OwnerReferences; len(refs) != 1 || refs[0].Name != "owner" {
+		t.Fatalf("unexpected owner references: %v", refs)
+	}
+}
+
+func TestAddCredHashToPodTemplate(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(newTestSecret(testSecretRef.Name, map[string]string{"password": "admin"}))
+	template := &corev1.PodTemplateSpec{}
+	template.Annotations = map[string]string{"app": "db"}
+	if err := m.AddCredHashToPodTemplate(ctx, []types.NamespacedName{testSecretRef}, template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dataHash, err := m.CalculateSecretDataHash(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if template.Annotations[GetAnnotationName(0)] != dataHash || template.Annotations["app"] != "db" {
+		t.Fatalf("unexpected pod template annotations: %v", template.Annotations)
+	}
+
+	updateTestSecret(t, k8sClient, testSecretRef.Name, map[string]string{"password": "new-admin"})
+	newHash, err := m.CalculateSecretDataHash(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newHash == dataHash {
+		t.Fatalf("hash must change with secret data")
+	}
+}
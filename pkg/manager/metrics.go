@@ -0,0 +1,62 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	rotationAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_rotation_attempts_total",
+		Help: "Number of credentials rotation attempts",
+	}, []string{"namespace", "secret"})
+	rotationSuccesses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_rotation_successes_total",
+		Help: "Number of successful credentials rotations",
+	}, []string{"namespace", "secret"})
+	rotationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_rotation_failures_total",
+		Help: "Number of failed credentials rotations",
+	}, []string{"namespace", "secret"})
+	changeCredsDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "credential_manager_change_creds_duration_seconds",
+		Help:    "Duration of credentials change function execution",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"namespace", "secret"})
+	scheduledRotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_scheduled_rotations_total",
+		Help: "Number of credentials rotations triggered by credentials age",
+	}, []string{"namespace", "secret"})
+	validationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "credential_manager_validation_failures_total",
+		Help: "Number of credentials rejected by validators",
+	}, []string{"namespace", "secret"})
+)
+
+// Collectors returns Prometheus collectors of the manager package.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{rotationAttempts, rotationSuccesses, rotationFailures, changeCredsDuration, scheduledRotations,
+		validationFailures}
+}
+
+// RegisterMetrics registers manager collectors, e.g. in controller-runtime metrics.Registry.
+// Informer collectors are registered separately with informer.RegisterMetrics.
+func RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range Collectors() {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
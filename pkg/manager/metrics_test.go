@@ -0,0 +1,35 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/informer"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRegisterMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if err := RegisterMetrics(registry); err != nil {
+		t.Fatalf("cannot register manager metrics: %v", err)
+	}
+	if err := informer.RegisterMetrics(registry); err != nil {
+		t.Fatalf("cannot register informer metrics together with manager metrics: %v", err)
+	}
+	if err := RegisterMetrics(registry); err == nil {
+		t.Fatalf("second registration of manager metrics must fail")
+	}
+}
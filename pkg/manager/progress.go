@@ -0,0 +1,97 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AppliedKeysAnnotation is set on "-old" copy and contains keys applied by partially failed credentials change.
+const AppliedKeysAnnotation = "credentials-applied-keys"
+
+// Progress records keys applied by changeCredsFunc in transactional mode.
+type Progress struct {
+	ctx         context.Context
+	manager     *CredentialManager
+	secretRef   types.NamespacedName
+	newSecret   *corev1.Secret
+	appliedKeys []string
+}
+
+func (m *CredentialManager) newProgress(ctx context.Context, secretRef types.NamespacedName, newSecret, oldSecret *corev1.Secret) *Progress {
+	return &Progress{
+		ctx:         ctx,
+		manager:     m,
+		secretRef:   secretRef,
+		newSecret:   newSecret,
+		appliedKeys: GetAppliedKeys(oldSecret),
+	}
+}
+
+// MarkApplied stores values of the applied keys in the previous credentials store, removed keys are removed from the copy.
+func (p *Progress) MarkApplied(keys ...string) error {
+	err := p.manager.store.Update(p.ctx, p.secretRef, func(secret *corev1.Secret) error {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		for _, key := range keys {
+			if value, found := p.newSecret.Data[key]; found {
+				secret.Data[key] = value
+			} else {
+				delete(secret.Data, key)
+			}
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[AppliedKeysAnnotation] = strings.Join(utils.MergeKeys(GetAppliedKeys(secret), keys), ",")
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot store applied keys of %s secret: %w", p.secretRef, err)
+	}
+	p.appliedKeys = utils.MergeKeys(p.appliedKeys, keys)
+	return nil
+}
+
+// MarkCredentialApplied stores username and password keys of the credential as applied.
+func (p *Progress) MarkCredentialApplied(credential Credential) error {
+	keys := []string{credential.PasswordKey}
+	if credential.UsernameKey != "" {
+		keys = append(keys, credential.UsernameKey)
+	}
+	return p.MarkApplied(keys...)
+}
+
+// AppliedKeys returns keys applied since the last successful credentials change.
+func (p *Progress) AppliedKeys() []string {
+	return slices.Clone(p.appliedKeys)
+}
+
+// GetAppliedKeys returns keys stored in AppliedKeysAnnotation of "-old" copy.
+func GetAppliedKeys(oldSecret *corev1.Secret) []string {
+	value := oldSecret.Annotations[AppliedKeysAnnotation]
+	if value == "" {
+		return nil
+	}
+	return utils.SplitList(value)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestActualizeCredsTransactionalResumesAfterMarkApplied(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testSecretRef.Name, Namespace: testSecretRef.Namespace},
+		Data: map[string][]byte{
+			"password":             []byte("new-admin"),
+			"replication-password": []byte("new-replicator"),
+		},
+	}
+	lock.Acquire(secret, "hook", time.Hour, time.Now())
+	oldSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testSecretRef.Name + "-old", Namespace: testSecretRef.Namespace},
+		Data: map[string][]byte{
+			"password":             []byte("old-admin"),
+			"replication-password": []byte("old-replicator"),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret, oldSecret).Build()
+	m := NewCredentialManager(k8sClient, k8sfake.NewClientset(), testSecretRef.Namespace)
+
+	// The first attempt applies only password key and fails
+	errApply := errors.New("replication user is not available")
+	err := m.ActualizeCredsTransactional(ctx, testSecretRef, func(diff *CredsDiff, progress *Progress) error {
+		if !reflect.DeepEqual(diff.ChangedKeys, []string{"password", "replication-password"}) {
+			t.Errorf("unexpected changed keys of the first attempt: %v", diff.ChangedKeys)
+		}
+		if err := progress.MarkApplied("password"); err != nil {
+			return err
+		}
+		return errApply
+	})
+	if !errors.Is(err, errApply) {
+		t.Fatalf("unexpected error of the first attempt: %v", err)
+	}
+	stored := getOldSecret(t, k8sClient)
+	if string(stored.Data["password"]) != "new-admin" || string(stored.Data["replication-password"]) != "old-replicator" {
+		t.Fatalf("only applied key must be stored in the copy, got password %q and replication-password %q",
+			stored.Data["password"], stored.Data["replication-password"])
+	}
+	if applied := GetAppliedKeys(stored); !reflect.DeepEqual(applied, []string{"password"}) {
+		t.Fatalf("unexpected applied keys annotation: %v", applied)
+	}
+	current := &corev1.Secret{}
+	if err = k8sClient.Get(ctx, testSecretRef, current); err != nil {
+		t.Fatalf("cannot get secret: %v", err)
+	}
+	if !lock.IsLocked(current, time.Now()) {
+		t.Fatalf("secret must stay locked after failed change")
+	}
+
+	// The second attempt receives only the remaining key
+	err = m.ActualizeCredsTransactional(ctx, testSecretRef, func(diff *CredsDiff, progress *Progress) error {
+		if !reflect.DeepEqual(diff.ChangedKeys, []string{"replication-password"}) {
+			t.Errorf("unexpected changed keys of the second attempt: %v", diff.ChangedKeys)
+		}
+		if applied := progress.AppliedKeys(); !reflect.DeepEqual(applied, []string{"password"}) {
+			t.Errorf("unexpected applied keys of the second attempt: %v", applied)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error of the second attempt: %v", err)
+	}
+	stored = getOldSecret(t, k8sClient)
+	if !reflect.DeepEqual(stored.Data, secret.Data) {
+		t.Fatalf("copy must contain new credentials after successful change")
+	}
+	if _, found := stored.Annotations[AppliedKeysAnnotation]; found {
+		t.Fatalf("applied keys annotation must be removed after successful change")
+	}
+	if err = k8sClient.Get(ctx, testSecretRef, current); err != nil {
+		t.Fatalf("cannot get secret: %v", err)
+	}
+	if lock.Get(current) != nil {
+		t.Fatalf("secret must be unlocked after successful change")
+	}
+}
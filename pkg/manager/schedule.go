@@ -0,0 +1,243 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/password"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// LastRotatedAnnotation contains time of the last successful credentials change in RFC3339 format
+	LastRotatedAnnotation = "credentials-last-rotated"
+	// MaxAgeAnnotation contains max age of the secret credentials, e.g. "90d" or "2160h"
+	MaxAgeAnnotation = "credentials-max-age"
+	// RotateKeysAnnotation contains comma separated keys generated by scheduled rotation
+	RotateKeysAnnotation = "credentials-rotate-keys"
+
+	DefaultScheduleInterval = 1 * time.Hour
+)
+
+func setLastRotated(secret *corev1.Secret, rotatedAt time.Time) {
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[LastRotatedAnnotation] = rotatedAt.UTC().Format(time.RFC3339)
+}
+
+// GetLastRotated returns time of the last credentials change stamped by ActualizeCreds, zero time is returned if it is unknown.
+func GetLastRotated(secret *corev1.Secret) time.Time {
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[LastRotatedAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return rotatedAt
+}
+
+// ParseMaxAge parses max age in Go duration format or in days with "d" suffix, e.g. "90d".
+func ParseMaxAge(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid max age %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// RotationScheduler generates new credentials of the secrets which are older than their max age.
+// Generated credentials are written into the secret, so they are applied by informer and ActualizeCreds.
+type RotationScheduler struct {
+	manager    *CredentialManager
+	secretRefs []types.NamespacedName
+	maxAge     time.Duration
+	policy     password.Policy
+	interval   time.Duration
+}
+
+// SchedulerOption configures RotationScheduler.
+type SchedulerOption func(s *RotationScheduler)
+
+// WithMaxAge sets max age of the secrets without MaxAgeAnnotation. By default only secrets with the annotation are rotated.
+func WithMaxAge(maxAge time.Duration) SchedulerOption {
+	return func(s *RotationScheduler) {
+		s.maxAge = maxAge
+	}
+}
+
+// WithPasswordPolicy sets policy of the generated passwords. By default password.DefaultPolicy is used.
+func WithPasswordPolicy(policy password.Policy) SchedulerOption {
+	return func(s *RotationScheduler) {
+		s.policy = policy
+	}
+}
+
+// WithScheduleInterval sets interval of the credentials age checks. By default DefaultScheduleInterval is used.
+func WithScheduleInterval(interval time.Duration) SchedulerOption {
+	return func(s *RotationScheduler) {
+		s.interval = interval
+	}
+}
+
+// NewRotationScheduler creates scheduler of the provided secrets.
+func NewRotationScheduler(secretNames []string, opts ...SchedulerOption) *RotationScheduler {
+	m := Default()
+	return m.NewRotationScheduler(m.SecretRefs(secretNames), opts...)
+}
+
+// NewRotationScheduler creates scheduler of the provided secrets.
+func (m *CredentialManager) NewRotationScheduler(secretRefs []types.NamespacedName, opts ...SchedulerOption) *RotationScheduler {
+	s := &RotationScheduler{
+		manager:    m,
+		secretRefs: secretRefs,
+		policy:     password.DefaultPolicy,
+		interval:   DefaultScheduleInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start checks credentials age each interval until the context is cancelled.
+// It implements controller-runtime Runnable, so scheduler may be added to the manager with mgr.Add.
+func (s *RotationScheduler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RotateExpired(ctx); err != nil {
+			logger.Error("Scheduled credentials rotation failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements controller-runtime LeaderElectionRunnable, scheduler runs on the leader only.
+func (s *RotationScheduler) NeedLeaderElection() bool {
+	return true
+}
+
+// RotateExpired generates new credentials of the secrets which are older than their max age and returns rotated secrets.
+// Processing continues when a secret fails, the returned error aggregates all failures.
+func (s *RotationScheduler) RotateExpired(ctx context.Context) ([]types.NamespacedName, error) {
+	var rotated []types.NamespacedName
+	var errs []error
+	for _, secretRef := range s.secretRefs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		isRotated, err := s.rotateIfExpired(ctx, secretRef, time.Now())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", secretRef, err))
+		} else if isRotated {
+			rotated = append(rotated, secretRef)
+		}
+	}
+	return rotated, errors.Join(errs...)
+}
+
+func (s *RotationScheduler) rotateIfExpired(ctx context.Context, secretRef types.NamespacedName, now time.Time) (bool, error) {
+	secret, err := s.manager.getSecret(ctx, secretRef)
+	if err != nil {
+		return false, err
+	}
+	maxAge := s.maxAge
+	if value := secret.Annotations[MaxAgeAnnotation]; value != "" {
+		if maxAge, err = ParseMaxAge(value); err != nil {
+			return false, err
+		}
+	}
+	if maxAge <= 0 {
+		return false, nil
+	}
+	lastRotated := GetLastRotated(secret)
+	if lastRotated.IsZero() {
+		// Age of the credentials is unknown, e.g. the secret was recreated or created before the scheduler,
+		// so the age is counted from the first check instead of rotating the secret immediately
+		logger.Info(fmt.Sprintf("Rotation time of secret %s is unknown, the secret is stamped with the current time", secretRef))
+		return false, s.manager.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+			if GetLastRotated(secret).IsZero() {
+				setLastRotated(secret, now)
+			}
+			return nil
+		})
+	}
+	if now.Sub(lastRotated) < maxAge {
+		return false, nil
+	}
+
+	// Generated credentials are applied by ActualizeCreds, so rotation waits for the previous change to be applied
+	if lock.GetState(secret, now) == lock.StateLocked {
+		logger.Info(fmt.Sprintf("Secret %s is locked, scheduled rotation is postponed", secretRef))
+		return false, nil
+	}
+	diff, err := s.manager.DiffCreds(ctx, secretRef)
+	if apierrors.IsNotFound(err) {
+		logger.Info(fmt.Sprintf("Previous credentials of secret %s are not stored, scheduled rotation is postponed", secretRef))
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if diff.HasChanges() {
+		logger.Info(fmt.Sprintf("Credentials change of secret %s is not applied yet, scheduled rotation is postponed", secretRef))
+		return false, nil
+	}
+
+	keys := utils.SplitList(secret.Annotations[RotateKeysAnnotation])
+	if len(keys) == 0 {
+		keys = passwordKeys(secret)
+	}
+	if len(keys) == 0 {
+		return false, fmt.Errorf("no keys to rotate are found, %s annotation may be used", RotateKeysAnnotation)
+	}
+	logger.Info(fmt.Sprintf("Credentials of secret %s are older than %s, new credentials will be generated", secretRef, maxAge),
+		zap.Strings("keys", keys), zap.Time("lastRotated", lastRotated))
+	if err = s.manager.Rotate(ctx, secretRef, keys, s.policy); err != nil {
+		return false, err
+	}
+	scheduledRotations.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+	return true, nil
+}
+
+// passwordKeys returns keys of the secret with "password" suffix.
+func passwordKeys(secret *corev1.Secret) []string {
+	keys := make([]string, 0)
+	for key := range secret.Data {
+		if strings.HasSuffix(strings.ToLower(key), passwordSuffix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
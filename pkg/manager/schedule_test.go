@@ -0,0 +1,190 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseMaxAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "90d", want: 90 * 24 * time.Hour},
+		{value: "2160h", want: 2160 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "xd", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseMaxAge(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMaxAge(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMaxAge(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func newScheduledSecret(lastRotated time.Time, annotations map[string]string) *corev1.Secret {
+	secret := newTestSecret(testSecretRef.Name, map[string]string{"user": "admin", "password": "secret", "replication-password": "replica"})
+	// Creation time must not be used as rotation time
+	secret.CreationTimestamp = metav1.NewTime(time.Now().Add(-365 * 24 * time.Hour))
+	secret.Annotations = annotations
+	if !lastRotated.IsZero() {
+		setLastRotated(secret, lastRotated)
+	}
+	return secret
+}
+
+func TestRotateExpired(t *testing.T) {
+	expired := time.Now().Add(-48 * time.Hour)
+	oldCopy := newTestSecret(testSecretRef.Name+"-old", map[string]string{"user": "admin", "password": "secret", "replication-password": "replica"})
+	noKeysSecret := newScheduledSecret(expired, nil)
+	noKeysSecret.Data = map[string][]byte{"user": []byte("admin")}
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		oldCopy     *corev1.Secret
+		maxAge      time.Duration
+		wantRotated []string
+		wantErr     string
+	}{
+		{name: "expired", secret: newScheduledSecret(expired, nil), oldCopy: oldCopy, maxAge: 24 * time.Hour,
+			wantRotated: []string{"password", "replication-password"}},
+		{name: "max age annotation", secret: newScheduledSecret(expired, map[string]string{MaxAgeAnnotation: "1d"}), oldCopy: oldCopy,
+			wantRotated: []string{"password", "replication-password"}},
+		{name: "rotate keys annotation", secret: newScheduledSecret(expired, map[string]string{RotateKeysAnnotation: "user"}), oldCopy: oldCopy,
+			maxAge: 24 * time.Hour, wantRotated: []string{"user"}},
+		{name: "not expired", secret: newScheduledSecret(time.Now().Add(-time.Hour), nil), oldCopy: oldCopy, maxAge: 24 * time.Hour},
+		{name: "annotation overrides max age", secret: newScheduledSecret(expired, map[string]string{MaxAgeAnnotation: "7d"}), oldCopy: oldCopy,
+			maxAge: 24 * time.Hour},
+		{name: "no max age", secret: newScheduledSecret(expired, nil), oldCopy: oldCopy},
+		{name: "no copy", secret: newScheduledSecret(expired, nil), maxAge: 24 * time.Hour},
+		{name: "change is not applied", secret: newScheduledSecret(expired, nil), maxAge: 24 * time.Hour,
+			oldCopy: newTestSecret(testSecretRef.Name+"-old", map[string]string{"user": "admin", "password": "previous", "replication-password": "replica"})},
+		{name: "invalid max age", secret: newScheduledSecret(expired, map[string]string{MaxAgeAnnotation: "soon"}), oldCopy: oldCopy,
+			wantErr: "invalid duration"},
+		{name: "no keys", secret: noKeysSecret, oldCopy: newTestSecret(testSecretRef.Name+"-old", map[string]string{"user": "admin"}),
+			maxAge: 24 * time.Hour, wantErr: "no keys to rotate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []client.Object{tt.secret}
+			if tt.oldCopy != nil {
+				objects = append(objects, tt.oldCopy.DeepCopy())
+			}
+			m, k8sClient := newTestManager(objects...)
+			scheduler := m.NewRotationScheduler([]types.NamespacedName{testSecretRef}, WithMaxAge(tt.maxAge))
+
+			rotated, err := scheduler.RotateExpired(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error %v must contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (len(rotated) > 0) != (len(tt.wantRotated) > 0) {
+				t.Fatalf("rotated secrets = %v, want rotated keys %v", rotated, tt.wantRotated)
+			}
+
+			secret := getTestSecret(t, k8sClient, testSecretRef.Name)
+			var changed []string
+			for _, key := range []string{"password", "replication-password", "user"} {
+				if string(secret.Data[key]) != string(tt.secret.Data[key]) {
+					changed = append(changed, key)
+				}
+			}
+			if strings.Join(changed, ",") != strings.Join(tt.wantRotated, ",") {
+				t.Errorf("changed keys = %v, want %v", changed, tt.wantRotated)
+			}
+		})
+	}
+}
+
+func TestRotateExpiredLocked(t *testing.T) {
+	secret := newScheduledSecret(time.Now().Add(-48*time.Hour), nil)
+	lock.Acquire(secret, "hook", time.Hour, time.Now())
+	oldCopy := newTestSecret(testSecretRef.Name+"-old", map[string]string{"user": "admin", "password": "secret", "replication-password": "replica"})
+	m, k8sClient := newTestManager(secret, oldCopy)
+
+	scheduler := m.NewRotationScheduler([]types.NamespacedName{testSecretRef}, WithMaxAge(24*time.Hour))
+	rotated, err := scheduler.RotateExpired(context.Background())
+	if err != nil || len(rotated) > 0 {
+		t.Fatalf("rotation of locked secret must be postponed, got %v, %v", rotated, err)
+	}
+	if password := getTestSecret(t, k8sClient, testSecretRef.Name).Data["password"]; string(password) != "secret" {
+		t.Errorf("password of locked secret is changed")
+	}
+}
+
+func TestRotateExpiredStampsUnknownRotationTime(t *testing.T) {
+	oldCopy := newTestSecret(testSecretRef.Name+"-old", map[string]string{"user": "admin", "password": "secret", "replication-password": "replica"})
+	m, k8sClient := newTestManager(newScheduledSecret(time.Time{}, nil), oldCopy)
+	scheduler := m.NewRotationScheduler([]types.NamespacedName{testSecretRef}, WithMaxAge(24*time.Hour))
+
+	before := time.Now().Truncate(time.Second)
+	rotated, err := scheduler.RotateExpired(context.Background())
+	if err != nil || len(rotated) > 0 {
+		t.Fatalf("secret with unknown rotation time must not be rotated, got %v, %v", rotated, err)
+	}
+	secret := getTestSecret(t, k8sClient, testSecretRef.Name)
+	if string(secret.Data["password"]) != "secret" {
+		t.Errorf("password is changed")
+	}
+	stamp := GetLastRotated(secret)
+	if stamp.Before(before) || stamp.After(time.Now()) {
+		t.Fatalf("secret must be stamped with the check time, got %s", stamp)
+	}
+
+	// The next check keeps the stamp and doesn't rotate the secret before max age
+	if rotated, err = scheduler.RotateExpired(context.Background()); err != nil || len(rotated) > 0 {
+		t.Fatalf("secret must not be rotated before max age, got %v, %v", rotated, err)
+	}
+	if got := GetLastRotated(getTestSecret(t, k8sClient, testSecretRef.Name)); !got.Equal(stamp) {
+		t.Errorf("stamp is changed from %s to %s", stamp, got)
+	}
+}
+
+func TestRotateExpiredContinuesAfterFailure(t *testing.T) {
+	expired := time.Now().Add(-48 * time.Hour)
+	oldCopy := newTestSecret(testSecretRef.Name+"-old", map[string]string{"user": "admin", "password": "secret", "replication-password": "replica"})
+	m, _ := newTestManager(newScheduledSecret(expired, nil), oldCopy)
+	missingRef := types.NamespacedName{Namespace: testSecretRef.Namespace, Name: "missing"}
+	scheduler := m.NewRotationScheduler([]types.NamespacedName{missingRef, testSecretRef}, WithMaxAge(24*time.Hour))
+
+	rotated, err := scheduler.RotateExpired(context.Background())
+	if err == nil || !strings.Contains(err.Error(), missingRef.String()) {
+		t.Errorf("error must describe the missing secret, got %v", err)
+	}
+	if len(rotated) != 1 || rotated[0] != testSecretRef {
+		t.Errorf("rotated secrets = %v, want %v", rotated, []types.NamespacedName{testSecretRef})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SecretStatus describes state of the managed credentials secret. Secret values are never included.
+type SecretStatus struct {
+	Secret types.NamespacedName
+
+	// LockState is lock state of the secret, Lock is its lock record, nil if secret is not locked
+	LockState lock.State
+	Lock      *lock.Record
+
+	// OldCopyExists reports if previous credentials are stored
+	OldCopyExists bool
+	// SecretDiff contains keys changed since the previous credentials were saved
+	utils.SecretDiff
+	// AppliedKeys are keys applied by partially failed credentials change
+	AppliedKeys []string
+
+	// LastRotated is time of the last credentials change stamped by ActualizeCreds, zero if it is unknown
+	LastRotated time.Time
+
+	// ValidationErrors are violations of the validation rules stored in ValidationErrorsAnnotation
+	ValidationErrors string `json:",omitempty"`
+
+	// DataHash is hash of the secret data calculated the same way as CalculateSecretDataHash
+	DataHash string
+
+	OwnerReferences        []metav1.OwnerReference
+	OldCopyOwnerReferences []metav1.OwnerReference
+
+	// Error describes why status of the secret is incomplete
+	Error string `json:",omitempty"`
+}
+
+// Status returns status of the provided secrets.
+func Status(secretNames []string) ([]SecretStatus, error) {
+	m := Default()
+	return m.Status(context.Background(), m.SecretRefs(secretNames))
+}
+
+// Status returns status of the provided secrets. Processing continues when a secret fails,
+// its status contains the error and the returned error aggregates all failures.
+func (m *CredentialManager) Status(ctx context.Context, secretRefs []types.NamespacedName) ([]SecretStatus, error) {
+	statuses := make([]SecretStatus, 0, len(secretRefs))
+	var errs []error
+	for _, secretRef := range secretRefs {
+		status, err := m.secretStatus(ctx, secretRef)
+		if err != nil {
+			status.Error = err.Error()
+			errs = append(errs, fmt.Errorf("secret %s: %w", secretRef, err))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, errors.Join(errs...)
+}
+
+func (m *CredentialManager) secretStatus(ctx context.Context, secretRef types.NamespacedName) (SecretStatus, error) {
+	status := SecretStatus{Secret: secretRef}
+	secret := &corev1.Secret{}
+	if err := m.client.Get(ctx, secretRef, secret); err != nil {
+		return status, err
+	}
+	status.LockState = lock.GetState(secret, time.Now())
+	status.Lock = lock.Get(secret)
+	status.OwnerReferences = secret.OwnerReferences
+	status.LastRotated = GetLastRotated(secret)
+	status.ValidationErrors = secret.Annotations[ValidationErrorsAnnotation]
+	dataHash, err := hash(secret.Data)
+	if err != nil {
+		return status, err
+	}
+	status.DataHash = dataHash
+
+	oldSecret, err := m.store.Load(ctx, secretRef)
+	if apierrors.IsNotFound(err) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+	status.OldCopyExists = true
+	status.SecretDiff = utils.DiffSecrets(oldSecret, m.store.Comparable(oldSecret, secret), m.diffOptions)
+	status.AppliedKeys = GetAppliedKeys(oldSecret)
+	status.OldCopyOwnerReferences = oldSecret.OwnerReferences
+	return status, nil
+}
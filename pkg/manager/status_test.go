@@ -0,0 +1,92 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/lock"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStatus(t *testing.T) {
+	secret := newTestSecret(testSecretRef.Name, map[string]string{"password": "new-secret", "user": "admin", "token": "abc"})
+	lock.Acquire(secret, "hook", time.Hour, time.Now())
+	oldSecret := newTestSecret(testSecretRef.Name+"-old", map[string]string{"password": "old-secret", "user": "admin", "cert": "pem"})
+	plainSecret := newTestSecret("plain", map[string]string{"password": "plain-secret"})
+	missingRef := types.NamespacedName{Namespace: testSecretRef.Namespace, Name: "missing"}
+	plainRef := types.NamespacedName{Namespace: testSecretRef.Namespace, Name: "plain"}
+	m, _ := newTestManager(secret, oldSecret, plainSecret)
+
+	statuses, err := m.Status(context.Background(), []types.NamespacedName{testSecretRef, missingRef, plainRef})
+	if err == nil || !strings.Contains(err.Error(), missingRef.String()) {
+		t.Errorf("error must describe the missing secret, got %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("status of every secret must be returned, got %d", len(statuses))
+	}
+
+	status := statuses[0]
+	if status.Secret != testSecretRef || status.LockState != lock.StateLocked || status.Lock == nil || status.Lock.Holder != "hook" {
+		t.Errorf("unexpected lock status of the locked secret: %+v", status)
+	}
+	if !status.OldCopyExists {
+		t.Errorf("-old copy must be reported")
+	}
+	if !reflect.DeepEqual(status.AddedKeys, []string{"token"}) || !reflect.DeepEqual(status.RemovedKeys, []string{"cert"}) ||
+		!reflect.DeepEqual(status.ChangedKeys, []string{"password"}) {
+		t.Errorf("unexpected diff: added %v, removed %v, changed %v", status.AddedKeys, status.RemovedKeys, status.ChangedKeys)
+	}
+	if wantHash, _ := hash(secret.Data); status.DataHash != wantHash {
+		t.Errorf("data hash = %q, want %q", status.DataHash, wantHash)
+	}
+	if status.Error != "" {
+		t.Errorf("unexpected error of the locked secret: %s", status.Error)
+	}
+
+	if statuses[1].Secret != missingRef || statuses[1].Error == "" {
+		t.Errorf("status of the missing secret must contain the error: %+v", statuses[1])
+	}
+
+	status = statuses[2]
+	if status.LockState != lock.StateUnlocked || status.Lock != nil || status.OldCopyExists || status.Error != "" {
+		t.Errorf("unexpected status of the secret without -old copy: %+v", status)
+	}
+}
+
+func TestStatusDoesNotContainValues(t *testing.T) {
+	secret := newTestSecret(testSecretRef.Name, map[string]string{"password": "new-secret"})
+	oldSecret := newTestSecret(testSecretRef.Name+"-old", map[string]string{"password": "old-secret"})
+	m, _ := newTestManager(secret, oldSecret)
+
+	statuses, err := m.Status(context.Background(), []types.NamespacedName{testSecretRef})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := json.Marshal(statuses)
+	if err != nil {
+		t.Fatalf("cannot marshal status: %v", err)
+	}
+	for _, value := range []string{"new-secret", "old-secret"} {
+		if strings.Contains(string(data), value) {
+			t.Errorf("status %s contains secret value %q", data, value)
+		}
+	}
+}
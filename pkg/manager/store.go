@@ -0,0 +1,294 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"maps"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StoreSecret keeps previous credentials as is in "-old" secret
+	StoreSecret = "secret"
+	// StoreHash keeps only salted hashes of previous credentials in "-old" secret
+	StoreHash = "hash"
+
+	// PreviousFormatAnnotation is set on "-old" secret which data is not stored as is
+	PreviousFormatAnnotation = "credentials-previous-format"
+	// PreviousSaltAnnotation contains salt of the hashes stored in "-old" secret
+	PreviousSaltAnnotation = "credentials-previous-salt"
+)
+
+// PreviousCredsStore keeps previous version of the credentials secret, which is compared with the current version
+// to detect credentials change and passed to changeCredsFunc.
+type PreviousCredsStore interface {
+	// Load returns previous version of the secret, NotFound error is returned if it is not stored.
+	Load(ctx context.Context, secretRef types.NamespacedName) (*corev1.Secret, error)
+	// Save applies mutate function to the stored previous version of the secret, it is created if it doesn't exist.
+	Save(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret)) error
+	// Update applies mutate function to the stored previous version of the secret, NotFound error is returned if it is not stored.
+	Update(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret) error) error
+	// Comparable converts current version of the secret into the form of previous version returned by Load.
+	Comparable(previous, current *corev1.Secret) *corev1.Secret
+}
+
+// NewPreviousCredsStore creates store of the provided kind, StoreSecret, StoreHash or StoreEncrypted.
+// Encrypted store uses key secret from ENCRYPTION_KEY_SECRET and key ID from ENCRYPTION_KEY_ID environment variables,
+// key secret provided by name only is located in the provided namespace.
+func NewPreviousCredsStore(kind string, k8sClient client.Client, namespace string) (PreviousCredsStore, error) {
+	switch kind {
+	case StoreSecret, "":
+		return NewSecretStore(k8sClient), nil
+	case StoreHash:
+		return NewHashStore(k8sClient), nil
+	case StoreEncrypted:
+		keySecretName := utils.GetEnv("ENCRYPTION_KEY_SECRET", "")
+		if keySecretName == "" {
+			return nil, fmt.Errorf("ENCRYPTION_KEY_SECRET is required for %s previous credentials store", StoreEncrypted)
+		}
+		return NewEncryptedStore(k8sClient, utils.GetSecretRef(keySecretName, namespace),
+			utils.GetEnv("ENCRYPTION_KEY_ID", "")), nil
+	}
+	return nil, fmt.Errorf("unknown previous credentials store %q", kind)
+}
+
+// SecretStore keeps previous credentials as is in "-old" secret. It is the default store.
+type SecretStore struct {
+	client client.Client
+}
+
+// NewSecretStore creates store which keeps previous credentials as is in "-old" secret.
+func NewSecretStore(k8sClient client.Client) *SecretStore {
+	return &SecretStore{client: k8sClient}
+}
+
+// Load returns "-old" copy of the secret. Copy which data is not stored as is can't be loaded.
+func (s *SecretStore) Load(ctx context.Context, secretRef types.NamespacedName) (*corev1.Secret, error) {
+	previous, err := s.get(ctx, secretRef)
+	if err != nil {
+		return nil, err
+	}
+	if format := previous.Annotations[PreviousFormatAnnotation]; format != "" {
+		return nil, fmt.Errorf("previous credentials of secret %s are stored in %s format", secretRef, format)
+	}
+	return previous, nil
+}
+
+// Save creates or updates "-old" copy of the secret, data is stored as is.
+func (s *SecretStore) Save(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret)) error {
+	return s.save(ctx, secretRef, func(previous *corev1.Secret) error {
+		mutate(previous)
+		deleteFormatAnnotations(previous)
+		return nil
+	})
+}
+
+// Update updates existing "-old" copy of the secret.
+func (s *SecretStore) Update(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret) error) error {
+	return s.update(ctx, secretRef, mutate)
+}
+
+// Comparable returns current version of the secret as is.
+func (s *SecretStore) Comparable(_, current *corev1.Secret) *corev1.Secret {
+	return current
+}
+
+func (s *SecretStore) get(ctx context.Context, secretRef types.NamespacedName) (*corev1.Secret, error) {
+	previous := &corev1.Secret{}
+	if err := s.client.Get(ctx, utils.GetOldSecretRef(secretRef), previous); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// save creates or updates "-old" copy, other fields of the existing copy, e.g. owner references, are preserved.
+func (s *SecretStore) save(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret) error) error {
+	oldSecretRef := utils.GetOldSecretRef(secretRef)
+	isRetriable := func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultRetry, isRetriable, func() error {
+		previous := &corev1.Secret{}
+		err := s.client.Get(ctx, oldSecretRef, previous)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			previous = newOpaqueSecret(oldSecretRef)
+			if err = mutate(previous); err != nil {
+				return err
+			}
+			return s.client.Create(ctx, previous)
+		}
+		if err = mutate(previous); err != nil {
+			return err
+		}
+		return s.client.Update(ctx, previous)
+	})
+}
+
+// update reads "-old" copy, applies mutate function and updates it. On conflict the change is reapplied.
+func (s *SecretStore) update(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret) error) error {
+	oldSecretRef := utils.GetOldSecretRef(secretRef)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		previous := &corev1.Secret{}
+		if err := s.client.Get(ctx, oldSecretRef, previous); err != nil {
+			return err
+		}
+		if err := mutate(previous); err != nil {
+			return err
+		}
+		return s.client.Update(ctx, previous)
+	})
+}
+
+// HashStore keeps only salted hashes of previous credentials in "-old" secret, so values of previous credentials
+// are not exposed. changeCredsFunc receives hashes instead of previous values, so it must not rely on them.
+// Copies with plain data are converted on the next save.
+type HashStore struct {
+	secrets *SecretStore
+}
+
+// NewHashStore creates store which keeps only salted hashes of previous credentials in "-old" secret.
+func NewHashStore(k8sClient client.Client) *HashStore {
+	return &HashStore{secrets: NewSecretStore(k8sClient)}
+}
+
+// Load returns "-old" copy of the secret with hashed or plain data.
+func (s *HashStore) Load(ctx context.Context, secretRef types.NamespacedName) (*corev1.Secret, error) {
+	previous, err := s.secrets.get(ctx, secretRef)
+	if err != nil {
+		return nil, err
+	}
+	if format := previous.Annotations[PreviousFormatAnnotation]; format != "" && format != StoreHash {
+		return nil, fmt.Errorf("previous credentials of secret %s are stored in %s format", secretRef, format)
+	}
+	if previous.Annotations[PreviousFormatAnnotation] == StoreHash {
+		// Hashes can't be compared without salt, so all keys would be reported as changed
+		if _, err = storedSalt(previous); err != nil {
+			return nil, fmt.Errorf("previous credentials of secret %s can't be compared: %w", secretRef, err)
+		}
+	}
+	return previous, nil
+}
+
+// Save creates or updates "-old" copy of the secret, changed values are replaced with hashes.
+func (s *HashStore) Save(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret)) error {
+	return s.secrets.save(ctx, secretRef, func(previous *corev1.Secret) error {
+		return s.hashChanged(previous, func(previous *corev1.Secret) error {
+			mutate(previous)
+			return nil
+		})
+	})
+}
+
+// Update updates existing "-old" copy of the secret, changed values are replaced with hashes.
+func (s *HashStore) Update(ctx context.Context, secretRef types.NamespacedName, mutate func(previous *corev1.Secret) error) error {
+	return s.secrets.update(ctx, secretRef, func(previous *corev1.Secret) error {
+		return s.hashChanged(previous, mutate)
+	})
+}
+
+// Comparable returns current version of the secret with hashed data, if previous version is hashed.
+// Salt of the previous version returned by Load is valid.
+func (s *HashStore) Comparable(previous, current *corev1.Secret) *corev1.Secret {
+	if previous.Annotations[PreviousFormatAnnotation] != StoreHash {
+		return current
+	}
+	salt, _ := storedSalt(previous)
+	comparable := current.DeepCopy()
+	comparable.Data = make(map[string][]byte, len(current.Data)+len(current.StringData))
+	for key, value := range current.Data {
+		comparable.Data[key] = hashValue(salt, value)
+	}
+	for key, value := range current.StringData {
+		comparable.Data[key] = hashValue(salt, []byte(value))
+	}
+	comparable.StringData = nil
+	return comparable
+}
+
+// hashChanged applies mutate function and hashes values changed by it. Plain copy is hashed completely.
+func (s *HashStore) hashChanged(previous *corev1.Secret, mutate func(previous *corev1.Secret) error) error {
+	hashed := previous.Annotations[PreviousFormatAnnotation] == StoreHash
+	salt, err := storedSalt(previous)
+	if hashed && err != nil {
+		// Unchanged hashes would be hashed again with the new salt
+		return fmt.Errorf("previous credentials of secret %s/%s can't be updated: %w", previous.Namespace, previous.Name, err)
+	}
+	if !hashed {
+		salt = make([]byte, 32)
+		if _, err = rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	before := maps.Clone(previous.Data)
+	if err = mutate(previous); err != nil {
+		return err
+	}
+	// Data map may be shared with the current version of the secret
+	previous.Data = maps.Clone(previous.Data)
+	for key, value := range previous.Data {
+		if hashed && bytes.Equal(before[key], value) {
+			continue
+		}
+		previous.Data[key] = hashValue(salt, value)
+	}
+	deleteFormatAnnotations(previous)
+	if previous.Annotations == nil {
+		previous.Annotations = make(map[string]string)
+	}
+	previous.Annotations[PreviousFormatAnnotation] = StoreHash
+	previous.Annotations[PreviousSaltAnnotation] = base64.StdEncoding.EncodeToString(salt)
+	return nil
+}
+
+// storedSalt returns salt of the hashed copy.
+func storedSalt(previous *corev1.Secret) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(previous.Annotations[PreviousSaltAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("salt in %s annotation is invalid: %w", PreviousSaltAnnotation, err)
+	}
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("salt in %s annotation is empty", PreviousSaltAnnotation)
+	}
+	return salt, nil
+}
+
+func hashValue(salt, value []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(value)
+	return []byte(fmt.Sprintf("%x", mac.Sum(nil)))
+}
+
+// deleteFormatAnnotations removes annotations describing format of the stored data.
+func deleteFormatAnnotations(previous *corev1.Secret) {
+	for _, annotation := range []string{PreviousFormatAnnotation, PreviousSaltAnnotation, EncryptionKeyIDAnnotation, EncryptedDataKeyAnnotation} {
+		delete(previous.Annotations, annotation)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func storedSaltOf(t *testing.T, secret *corev1.Secret) []byte {
+	t.Helper()
+	salt, err := base64.StdEncoding.DecodeString(secret.Annotations[PreviousSaltAnnotation])
+	if err != nil || len(salt) == 0 {
+		t.Fatalf("invalid salt annotation: %v", secret.Annotations)
+	}
+	return salt
+}
+
+func setAnnotation(t *testing.T, k8sClient client.Client, name, annotation, value string) {
+	t.Helper()
+	secret := getTestSecret(t, k8sClient, name)
+	if value == "" {
+		delete(secret.Annotations, annotation)
+	} else {
+		secret.Annotations[annotation] = value
+	}
+	if err := k8sClient.Update(context.Background(), secret); err != nil {
+		t.Fatalf("cannot update %s secret: %v", name, err)
+	}
+}
+
+func TestHashStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewClientBuilder().Build()
+	store := NewHashStore(k8sClient)
+	data := map[string][]byte{"username": []byte("admin"), "password": []byte("s3cr3t")}
+	saveCreds(t, store, data)
+
+	stored := getOldSecret(t, k8sClient)
+	if stored.Annotations[PreviousFormatAnnotation] != StoreHash {
+		t.Fatalf("unexpected format annotations: %v", stored.Annotations)
+	}
+	salt := storedSaltOf(t, stored)
+	for key, value := range data {
+		if !bytes.Equal(stored.Data[key], hashValue(salt, value)) {
+			t.Errorf("key %s is not stored as salted hash", key)
+		}
+	}
+
+	previous, err := store.Load(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("cannot load previous credentials: %v", err)
+	}
+	current := newTestSecret(testSecretRef.Name, map[string]string{"username": "admin", "password": "s3cr3t"})
+	if diff := utils.DiffSecrets(previous, store.Comparable(previous, current), utils.DiffOptions{}); diff.HasChanges() {
+		t.Fatalf("unchanged credentials are reported as changed: %+v", diff)
+	}
+	current.Data["password"] = []byte("new-s3cr3t")
+	current.StringData = map[string]string{"port": "5432"}
+	comparable := store.Comparable(previous, current)
+	diff := utils.DiffSecrets(previous, comparable, utils.DiffOptions{})
+	if !reflect.DeepEqual(diff.ChangedKeys, []string{"password"}) || !reflect.DeepEqual(diff.AddedKeys, []string{"port"}) {
+		t.Fatalf("unexpected diff of changed credentials: %+v", diff)
+	}
+	if string(current.Data["password"]) != "new-s3cr3t" {
+		t.Fatalf("comparable version must not modify current secret")
+	}
+}
+
+func TestHashStoreUpdateKeepsUnchangedHashes(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewClientBuilder().Build()
+	store := NewHashStore(k8sClient)
+	saveCreds(t, store, map[string][]byte{"username": []byte("admin"), "password": []byte("s3cr3t")})
+	before := getOldSecret(t, k8sClient)
+
+	err := store.Update(ctx, testSecretRef, func(previous *corev1.Secret) error {
+		previous.Data["password"] = []byte("new-s3cr3t")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot update previous credentials: %v", err)
+	}
+	after := getOldSecret(t, k8sClient)
+	salt := storedSaltOf(t, before)
+	if !bytes.Equal(storedSaltOf(t, after), salt) {
+		t.Fatalf("salt must not change on update")
+	}
+	if !bytes.Equal(after.Data["username"], before.Data["username"]) {
+		t.Errorf("unchanged hash must be kept")
+	}
+	if !bytes.Equal(after.Data["password"], hashValue(salt, []byte("new-s3cr3t"))) {
+		t.Errorf("changed value must be hashed")
+	}
+}
+
+func TestHashStoreHashesPlainCopy(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewClientBuilder().
+		WithObjects(newTestSecret(utils.GetOldSecretName(testSecretRef.Name), map[string]string{"password": "s3cr3t"})).
+		Build()
+	store := NewHashStore(k8sClient)
+
+	previous, err := store.Load(ctx, testSecretRef)
+	if err != nil {
+		t.Fatalf("cannot load plain copy: %v", err)
+	}
+	current := newTestSecret(testSecretRef.Name, map[string]string{"password": "s3cr3t"})
+	if comparable := store.Comparable(previous, current); comparable != current {
+		t.Fatalf("plain copy must be compared with current version as is")
+	}
+
+	if err = store.Update(ctx, testSecretRef, func(previous *corev1.Secret) error { return nil }); err != nil {
+		t.Fatalf("cannot update plain copy: %v", err)
+	}
+	stored := getOldSecret(t, k8sClient)
+	if !bytes.Equal(stored.Data["password"], hashValue(storedSaltOf(t, stored), []byte("s3cr3t"))) {
+		t.Fatalf("plain copy must be hashed completely on update")
+	}
+}
+
+func TestHashStoreRejectsInvalidCopy(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		value      string
+	}{
+		{name: "missing salt", annotation: PreviousSaltAnnotation},
+		{name: "invalid salt", annotation: PreviousSaltAnnotation, value: "not base64!"},
+		{name: "other format", annotation: PreviousFormatAnnotation, value: StoreEncrypted},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sClient := fake.NewClientBuilder().Build()
+			store := NewHashStore(k8sClient)
+			saveCreds(t, store, map[string][]byte{"password": []byte("s3cr3t")})
+			setAnnotation(t, k8sClient, utils.GetOldSecretName(testSecretRef.Name), test.annotation, test.value)
+			tampered := getOldSecret(t, k8sClient)
+
+			if _, err := store.Load(ctx, testSecretRef); err == nil {
+				t.Errorf("load of invalid copy must fail")
+			}
+			err := store.Update(ctx, testSecretRef, func(previous *corev1.Secret) error {
+				previous.Data["password"] = []byte("new-s3cr3t")
+				return nil
+			})
+			if test.annotation == PreviousSaltAnnotation && err == nil {
+				t.Errorf("update of copy with invalid salt must fail")
+			}
+			if err != nil && !reflect.DeepEqual(getOldSecret(t, k8sClient).Data, tampered.Data) {
+				t.Errorf("failed update must not change stored hashes")
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/password"
+	"github.com/Netcracker/qubership-credential-manager/pkg/recorder"
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ValidationErrorsAnnotation contains violations of the validation rules by credentials of the secret
+	ValidationErrorsAnnotation = "credentials-validation-errors"
+)
+
+// Violation describes key of the secret which doesn't satisfy validation rule. Secret values are never included.
+type Violation struct {
+	Key     string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Key == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s: %s", v.Key, v.Message)
+}
+
+// Validator checks new credentials before they are accepted.
+// If previous credentials are not stored, diff.OldSecret is empty and all keys are added.
+type Validator interface {
+	Validate(diff *CredsDiff) []Violation
+}
+
+// ValidatorFunc allows to use ordinary function as Validator.
+type ValidatorFunc func(diff *CredsDiff) []Violation
+
+func (f ValidatorFunc) Validate(diff *CredsDiff) []Violation {
+	return f(diff)
+}
+
+// ValidationError is returned when credentials of the secret violate validation rules.
+type ValidationError struct {
+	Secret     types.NamespacedName
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("credentials of secret %s are invalid: %s", e.Secret, formatViolations(e.Violations))
+}
+
+// ValidationRules is Validator with the common rules for credentials.
+type ValidationRules struct {
+	// Password is checked for added and changed password keys: Length is minimal length,
+	// characters of each enabled class are required and Exclude contains forbidden characters
+	Password password.Policy
+	// PasswordKeys are keys checked with Password policy, by default keys with "password" suffix are checked
+	PasswordKeys []string
+	// RequiredKeys must be present in the secret and have non-empty values
+	RequiredKeys []string
+	// NotEqualPrevious rejects added and changed password keys with value equal to the previous value of any stored password key
+	NotEqualPrevious bool
+}
+
+// GetValidationRules returns validation rules configured by VALIDATION_MIN_LENGTH, VALIDATION_REQUIRED_CLASSES,
+// VALIDATION_FORBIDDEN_CHARS, VALIDATION_REQUIRED_KEYS and VALIDATION_NOT_EQUAL_PREVIOUS environment variables.
+func GetValidationRules() (ValidationRules, error) {
+	rules := ValidationRules{
+		Password:     password.Policy{Exclude: utils.GetEnv("VALIDATION_FORBIDDEN_CHARS", "")},
+		RequiredKeys: utils.SplitList(utils.GetEnv("VALIDATION_REQUIRED_KEYS", "")),
+	}
+	var err error
+	if rules.Password.Length, err = strconv.Atoi(utils.GetEnv("VALIDATION_MIN_LENGTH", "0")); err != nil {
+		return rules, fmt.Errorf("invalid VALIDATION_MIN_LENGTH: %w", err)
+	}
+	for _, class := range utils.SplitList(utils.GetEnv("VALIDATION_REQUIRED_CLASSES", "")) {
+		switch class {
+		case "lowercase":
+			rules.Password.Lowercase = true
+		case "uppercase":
+			rules.Password.Uppercase = true
+		case "digits":
+			rules.Password.Digits = true
+		case "special":
+			rules.Password.Special = true
+		default:
+			return rules, fmt.Errorf("invalid VALIDATION_REQUIRED_CLASSES: unknown character class %q", class)
+		}
+	}
+	if rules.NotEqualPrevious, err = strconv.ParseBool(utils.GetEnv("VALIDATION_NOT_EQUAL_PREVIOUS", "false")); err != nil {
+		return rules, fmt.Errorf("invalid VALIDATION_NOT_EQUAL_PREVIOUS: %w", err)
+	}
+	return rules, nil
+}
+
+// IsEmpty checks if no rules are configured.
+func (r ValidationRules) IsEmpty() bool {
+	return r.Password == password.Policy{} && len(r.RequiredKeys) == 0 && !r.NotEqualPrevious
+}
+
+// Validate checks the credentials change with the rules.
+func (r ValidationRules) Validate(diff *CredsDiff) []Violation {
+	var violations []Violation
+	for _, key := range r.RequiredKeys {
+		if len(diff.NewSecret.Data[key]) == 0 {
+			violations = append(violations, Violation{Key: key, Message: "required key is missing or empty"})
+		}
+	}
+	keys := r.PasswordKeys
+	if len(keys) == 0 {
+		keys = passwordKeys(diff.NewSecret)
+	}
+	for _, key := range keys {
+		value, found := diff.NewSecret.Data[key]
+		if !found || !diff.IsKeyChanged(key) {
+			continue
+		}
+		for _, message := range r.Password.Check(string(value)) {
+			violations = append(violations, Violation{Key: key, Message: message})
+		}
+	}
+	if r.NotEqualPrevious {
+		violations = append(violations, r.reusedPasswords(diff, keys)...)
+	}
+	return violations
+}
+
+// reusedPasswords returns changed password keys which reuse previous value of any stored password key.
+// Values are compared in the form of the stored copy, so hashed and encrypted copies are supported.
+func (r ValidationRules) reusedPasswords(diff *CredsDiff, keys []string) []Violation {
+	previousKeys := slices.Clone(r.PasswordKeys)
+	if len(previousKeys) == 0 {
+		previousKeys = passwordKeys(diff.OldSecret)
+	}
+	slices.Sort(previousKeys)
+	comparable := diff.ComparableSecret()
+	var violations []Violation
+	for _, key := range keys {
+		value, found := comparable.Data[key]
+		if !found || !diff.IsKeyChanged(key) {
+			continue
+		}
+		for _, previousKey := range previousKeys {
+			previousValue, found := diff.OldSecret.Data[previousKey]
+			if !found || !bytes.Equal(value, previousValue) {
+				continue
+			}
+			message := "password must not be equal to its previous value"
+			if previousKey != key {
+				message = fmt.Sprintf("password must not be equal to the previous value of %s", previousKey)
+			}
+			violations = append(violations, Violation{Key: key, Message: message})
+			break
+		}
+	}
+	return violations
+}
+
+// WithValidators adds validators of new credentials which are checked by PrepareOldCreds and ActualizeCreds.
+func WithValidators(validators ...Validator) Option {
+	return func(m *CredentialManager) {
+		m.validators = append(m.validators, validators...)
+	}
+}
+
+// validateSecret checks credentials of the secret against the previous credentials.
+func (m *CredentialManager) validateSecret(ctx context.Context, secretRef types.NamespacedName, secret *corev1.Secret) error {
+	if len(m.validators) == 0 {
+		return nil
+	}
+	oldSecret, err := m.store.Load(ctx, secretRef)
+	if apierrors.IsNotFound(err) {
+		return m.validateCreds(ctx, m.initialCredsDiff(secretRef, secret))
+	} else if err != nil {
+		return err
+	}
+	return m.validateCreds(ctx, m.newCredsDiff(oldSecret, secret))
+}
+
+// initialCredsDiff returns diff of the secret without stored previous credentials, all keys are added.
+func (m *CredentialManager) initialCredsDiff(secretRef types.NamespacedName, secret *corev1.Secret) *CredsDiff {
+	oldSecret := newOpaqueSecret(secretRef)
+	return newCredsDiff(oldSecret, secret, secret, utils.DiffSecrets(oldSecret, secret, m.diffOptions))
+}
+
+// newCredsDiff compares the secret with the previous credentials in the form returned by the store.
+func (m *CredentialManager) newCredsDiff(oldSecret, secret *corev1.Secret) *CredsDiff {
+	comparable := m.store.Comparable(oldSecret, secret)
+	return newCredsDiff(oldSecret, secret, comparable, utils.DiffSecrets(oldSecret, comparable, m.diffOptions))
+}
+
+// validateCreds checks the credentials change with the validators. Violations are posted as Warning Event
+// and stored in ValidationErrorsAnnotation of the secret, the annotation is removed when credentials become valid.
+func (m *CredentialManager) validateCreds(ctx context.Context, diff *CredsDiff) error {
+	if len(m.validators) == 0 {
+		return nil
+	}
+	secretRef := types.NamespacedName{Namespace: diff.NewSecret.Namespace, Name: diff.NewSecret.Name}
+	var violations []Violation
+	for _, validator := range m.validators {
+		violations = append(violations, validator.Validate(diff)...)
+	}
+	if len(violations) == 0 {
+		if _, found := diff.NewSecret.Annotations[ValidationErrorsAnnotation]; !found {
+			return nil
+		}
+		return m.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+			delete(secret.Annotations, ValidationErrorsAnnotation)
+			return nil
+		})
+	}
+
+	validationErr := &ValidationError{Secret: secretRef, Violations: violations}
+	logger.Error(fmt.Sprintf("Credentials of secret %s are not accepted", secretRef), zap.Error(validationErr))
+	validationFailures.WithLabelValues(secretRef.Namespace, secretRef.Name).Inc()
+	m.recorder.Warning(secretRef, recorder.ReasonValidationFailed, "Credentials validation failed: %s", formatViolations(violations))
+	err := m.updateSecret(ctx, secretRef, func(secret *corev1.Secret) error {
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[ValidationErrorsAnnotation] = formatViolations(violations)
+		return nil
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Validation errors of secret %s weren't stored", secretRef), zap.Error(err))
+	}
+	return validationErr
+}
+
+func formatViolations(violations []Violation) string {
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	return strings.Join(messages, "; ")
+}
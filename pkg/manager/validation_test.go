@@ -0,0 +1,198 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/password"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetValidationRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    ValidationRules
+		wantErr string
+	}{
+		{name: "empty", want: ValidationRules{RequiredKeys: []string{}}},
+		{
+			name: "all rules",
+			env: map[string]string{
+				"VALIDATION_MIN_LENGTH":         "12",
+				"VALIDATION_REQUIRED_CLASSES":   "lowercase, uppercase,digits,special",
+				"VALIDATION_FORBIDDEN_CHARS":    "$&",
+				"VALIDATION_REQUIRED_KEYS":      "username,password",
+				"VALIDATION_NOT_EQUAL_PREVIOUS": "true",
+			},
+			want: ValidationRules{
+				Password:         password.Policy{Length: 12, Lowercase: true, Uppercase: true, Digits: true, Special: true, Exclude: "$&"},
+				RequiredKeys:     []string{"username", "password"},
+				NotEqualPrevious: true,
+			},
+		},
+		{name: "invalid min length", env: map[string]string{"VALIDATION_MIN_LENGTH": "twelve"}, wantErr: "invalid VALIDATION_MIN_LENGTH"},
+		{name: "unknown class", env: map[string]string{"VALIDATION_REQUIRED_CLASSES": "digits,emoji"}, wantErr: `unknown character class "emoji"`},
+		{name: "invalid not equal previous", env: map[string]string{"VALIDATION_NOT_EQUAL_PREVIOUS": "sometimes"}, wantErr: "invalid VALIDATION_NOT_EQUAL_PREVIOUS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"VALIDATION_MIN_LENGTH", "VALIDATION_REQUIRED_CLASSES", "VALIDATION_FORBIDDEN_CHARS",
+				"VALIDATION_REQUIRED_KEYS", "VALIDATION_NOT_EQUAL_PREVIOUS"} {
+				t.Setenv(name, tt.env[name])
+			}
+			rules, err := GetValidationRules()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error %v must contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rules.IsEmpty() != (len(tt.env) == 0) {
+				t.Errorf("IsEmpty() = %t for environment %v", rules.IsEmpty(), tt.env)
+			}
+			if !reflect.DeepEqual(rules, tt.want) {
+				t.Errorf("GetValidationRules() = %+v, want %+v", rules, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCredentialManagerFromEnvInvalidRules(t *testing.T) {
+	t.Setenv("VALIDATION_MIN_LENGTH", "twelve")
+	_, err := NewCredentialManagerFromEnv(fake.NewClientBuilder().Build(), k8sfake.NewClientset(), testSecretRef.Namespace)
+	if err == nil {
+		t.Errorf("error is expected for invalid validation rules")
+	}
+}
+
+func TestValidationRulesValidate(t *testing.T) {
+	oldData := map[string]string{"username": "admin", "password": "Previous1", "replication-password": "Replica1"}
+	tests := []struct {
+		name    string
+		rules   ValidationRules
+		newData map[string]string
+		want    []string
+	}{
+		{
+			name:    "min length",
+			rules:   ValidationRules{Password: password.Policy{Length: 10}},
+			newData: map[string]string{"username": "admin", "password": "Short1", "replication-password": "Replica1"},
+			want:    []string{"password: length 6 is less than 10"},
+		},
+		{
+			name:    "unchanged keys are not checked",
+			rules:   ValidationRules{Password: password.Policy{Length: 10}},
+			newData: map[string]string{"username": "admin", "password": "LongEnough1", "replication-password": "Replica1"},
+		},
+		{
+			name:    "required classes",
+			rules:   ValidationRules{Password: password.Policy{Lowercase: true, Uppercase: true, Digits: true, Special: true}},
+			newData: map[string]string{"username": "admin", "password": "lowercase", "replication-password": "Replica1"},
+			want: []string{"password: at least one uppercase letter is required", "password: at least one digit is required",
+				"password: at least one special character is required"},
+		},
+		{
+			name:    "forbidden characters",
+			rules:   ValidationRules{Password: password.Policy{Exclude: "$&"}},
+			newData: map[string]string{"username": "admin", "password": "Pa$$word1", "replication-password": "Replica1"},
+			want:    []string{"password: forbidden characters are used"},
+		},
+		{
+			name:    "custom password keys",
+			rules:   ValidationRules{Password: password.Policy{Length: 10}, PasswordKeys: []string{"username"}},
+			newData: map[string]string{"username": "root", "password": "Short1", "replication-password": "Replica1"},
+			want:    []string{"username: length 4 is less than 10"},
+		},
+		{
+			name:    "required keys",
+			rules:   ValidationRules{RequiredKeys: []string{"username", "database"}},
+			newData: map[string]string{"username": "", "password": "Previous1", "replication-password": "Replica1"},
+			want:    []string{"username: required key is missing or empty", "database: required key is missing or empty"},
+		},
+		{
+			name:    "equal previous value",
+			rules:   ValidationRules{NotEqualPrevious: true},
+			newData: map[string]string{"username": "admin", "password": "Replica1", "replication-password": "Previous1"},
+			want: []string{"password: password must not be equal to the previous value of replication-password",
+				"replication-password: password must not be equal to the previous value of password"},
+		},
+		{
+			name:    "new values",
+			rules:   ValidationRules{NotEqualPrevious: true},
+			newData: map[string]string{"username": "admin", "password": "Next1", "replication-password": "Next2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager()
+			diff := m.newCredsDiff(newTestSecret(testSecretRef.Name+"-old", oldData), newTestSecret(testSecretRef.Name, tt.newData))
+			var got []string
+			for _, violation := range tt.rules.Validate(diff) {
+				got = append(got, violation.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSecretNotEqualPreviousWithHashStore(t *testing.T) {
+	ctx := context.Background()
+	secret := newTestSecret(testSecretRef.Name, map[string]string{"password": "Replica1", "replication-password": "Replica2"})
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	store := NewHashStore(k8sClient)
+	saveCreds(t, store, map[string][]byte{"password": []byte("Previous1"), "replication-password": []byte("Replica1")})
+	m := NewCredentialManager(k8sClient, k8sfake.NewClientset(), testSecretRef.Namespace,
+		WithPreviousCredsStore(store), WithValidators(ValidationRules{NotEqualPrevious: true}))
+
+	err := m.validateSecret(ctx, testSecretRef, getTestSecret(t, k8sClient, testSecretRef.Name))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("validation error is expected, got %v", err)
+	}
+	want := []Violation{{Key: "password", Message: "password must not be equal to the previous value of replication-password"}}
+	if !reflect.DeepEqual(validationErr.Violations, want) {
+		t.Errorf("violations = %v, want %v", validationErr.Violations, want)
+	}
+	annotation := getTestSecret(t, k8sClient, testSecretRef.Name).Annotations[ValidationErrorsAnnotation]
+	if annotation != formatViolations(want) {
+		t.Errorf("validation errors annotation = %q, want %q", annotation, formatViolations(want))
+	}
+	for _, value := range []string{"Previous1", "Replica1", "Replica2"} {
+		if strings.Contains(err.Error(), value) || strings.Contains(annotation, value) {
+			t.Errorf("secret value %q is reported", value)
+		}
+	}
+
+	// The annotation is removed when credentials become valid
+	updateTestSecret(t, k8sClient, testSecretRef.Name, map[string]string{"password": "Next1", "replication-password": "Replica2"})
+	if err = m.validateSecret(ctx, testSecretRef, getTestSecret(t, k8sClient, testSecretRef.Name)); err != nil {
+		t.Fatalf("unexpected error of valid credentials: %v", err)
+	}
+	if _, found := getTestSecret(t, k8sClient, testSecretRef.Name).Annotations[ValidationErrorsAnnotation]; found {
+		t.Errorf("validation errors annotation must be removed")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Character classes used by Policy.
+const (
+	LowercaseChars = "abcdefghijklmnopqrstuvwxyz"
+	UppercaseChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	DigitChars     = "0123456789"
+	// SpecialChars doesn't contain quotes, backslash and space, which often require escaping
+	SpecialChars = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+)
+
+// DefaultPolicy generates 24 characters passwords of letters and digits.
+var DefaultPolicy = Policy{Length: 24, Lowercase: true, Uppercase: true, Digits: true}
+
+// Policy describes generated passwords. Generated password contains at least one character of each enabled class.
+type Policy struct {
+	Length int
+
+	Lowercase bool
+	Uppercase bool
+	Digits    bool
+	Special   bool
+
+	// Exclude contains characters which must not be used, e.g. characters forbidden by the application
+	Exclude string
+}
+
+// Validate checks that passwords may be generated with the policy.
+func (p Policy) Validate() error {
+	classes := p.classes()
+	if len(classes) == 0 {
+		return fmt.Errorf("no character classes are enabled")
+	}
+	for _, class := range classes {
+		if class == "" {
+			return fmt.Errorf("all characters of an enabled class are excluded")
+		}
+	}
+	if p.Length < len(classes) {
+		return fmt.Errorf("length %d is less than number of enabled character classes %d", p.Length, len(classes))
+	}
+	return nil
+}
+
+// Check returns violations of the policy by the password: length less than Length,
+// absence of characters of an enabled class and presence of excluded characters. Password characters are never included.
+func (p Policy) Check(password string) []string {
+	var violations []string
+	if length := len([]rune(password)); length < p.Length {
+		violations = append(violations, fmt.Sprintf("length %d is less than %d", length, p.Length))
+	}
+	for _, class := range []struct {
+		enabled bool
+		chars   string
+		name    string
+	}{
+		{p.Lowercase, LowercaseChars, "lowercase letter"},
+		{p.Uppercase, UppercaseChars, "uppercase letter"},
+		{p.Digits, DigitChars, "digit"},
+		{p.Special, SpecialChars, "special character"},
+	} {
+		if class.enabled && !strings.ContainsAny(password, class.chars) {
+			violations = append(violations, fmt.Sprintf("at least one %s is required", class.name))
+		}
+	}
+	if p.Exclude != "" && strings.ContainsAny(password, p.Exclude) {
+		violations = append(violations, "forbidden characters are used")
+	}
+	return violations
+}
+
+// classes returns characters of the enabled classes without excluded characters.
+func (p Policy) classes() []string {
+	var classes []string
+	for _, class := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{p.Lowercase, LowercaseChars},
+		{p.Uppercase, UppercaseChars},
+		{p.Digits, DigitChars},
+		{p.Special, SpecialChars},
+	} {
+		if !class.enabled {
+			continue
+		}
+		classes = append(classes, strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.Exclude, r) {
+				return -1
+			}
+			return r
+		}, class.chars))
+	}
+	return classes
+}
+
+// Generate generates password according to the policy using crypto/rand.
+func Generate(policy Policy) (string, error) {
+	if err := policy.Validate(); err != nil {
+		return "", fmt.Errorf("invalid password policy: %w", err)
+	}
+	classes := policy.classes()
+	password := make([]byte, 0, policy.Length)
+	for _, class := range classes {
+		char, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+	}
+	allChars := strings.Join(classes, "")
+	for len(password) < policy.Length {
+		char, err := randomChar(allChars)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+	}
+	// Characters of each class are placed first, so they are shuffled
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// GenerateValues generates passwords for the provided keys.
+func GenerateValues(keys []string, policy Policy) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		password, err := Generate(policy)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = []byte(password)
+	}
+	return values, nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
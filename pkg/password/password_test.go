@@ -0,0 +1,141 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{name: "default", policy: DefaultPolicy},
+		{name: "no classes", policy: Policy{Length: 10}, wantErr: "no character classes"},
+		{name: "excluded class", policy: Policy{Length: 10, Lowercase: true, Digits: true, Exclude: DigitChars}, wantErr: "are excluded"},
+		{name: "partially excluded class", policy: Policy{Length: 10, Digits: true, Exclude: "012345678"}},
+		{name: "too short", policy: Policy{Length: 3, Lowercase: true, Uppercase: true, Digits: true, Special: true}, wantErr: "less than number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error %v must contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{name: "default", policy: DefaultPolicy},
+		{name: "all classes", policy: Policy{Length: 8, Lowercase: true, Uppercase: true, Digits: true, Special: true}},
+		{name: "minimal length", policy: Policy{Length: 4, Lowercase: true, Uppercase: true, Digits: true, Special: true}},
+		{name: "excluded characters", policy: Policy{Length: 32, Lowercase: true, Digits: true, Special: true, Exclude: "abcdefghijklmnopqrstuvwxy012345678!#$%"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Characters are random, so a number of passwords is checked
+			for i := 0; i < 100; i++ {
+				password, err := Generate(tt.policy)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(password) != tt.policy.Length {
+					t.Fatalf("password length = %d, want %d", len(password), tt.policy.Length)
+				}
+				if violations := tt.policy.Check(password); len(violations) > 0 {
+					t.Fatalf("generated password violates the policy: %v", violations)
+				}
+				if tt.policy.Exclude != "" && strings.ContainsAny(password, tt.policy.Exclude) {
+					t.Fatalf("password %q contains excluded characters", password)
+				}
+				if !tt.policy.Special && strings.ContainsAny(password, SpecialChars) {
+					t.Fatalf("password %q contains special characters of disabled class", password)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateInvalidPolicy(t *testing.T) {
+	policy := Policy{Length: 10, Lowercase: true, Exclude: LowercaseChars}
+	if password, err := Generate(policy); err == nil {
+		t.Errorf("password %q is generated with fully excluded class", password)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	policy := Policy{Length: 8, Lowercase: true, Uppercase: true, Digits: true, Special: true, Exclude: "@"}
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{name: "valid", password: "abcD1234!"},
+		{name: "too short", password: "aB1!", want: []string{"length 4 is less than 8"}},
+		{name: "no uppercase and special", password: "abcd1234", want: []string{"at least one uppercase letter is required", "at least one special character is required"}},
+		{name: "no digits", password: "abcdEFGH!", want: []string{"at least one digit is required"}},
+		{name: "no lowercase", password: "ABCD1234!", want: []string{"at least one lowercase letter is required"}},
+		{name: "forbidden character", password: "abcD1234@!", want: []string{"forbidden characters are used"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Check(tt.password)
+			if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("Check(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+			for _, violation := range got {
+				if strings.Contains(violation, tt.password) {
+					t.Errorf("violation %q contains the password", violation)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateValues(t *testing.T) {
+	values, err := GenerateValues([]string{"password", "replication-password"}, DefaultPolicy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("values of 2 keys are expected, got %d", len(values))
+	}
+	if string(values["password"]) == string(values["replication-password"]) {
+		t.Errorf("values of different keys must be generated separately")
+	}
+	for key, value := range values {
+		if violations := DefaultPolicy.Check(string(value)); len(violations) > 0 {
+			t.Errorf("value of %s key violates the policy: %v", key, violations)
+		}
+	}
+
+	if _, err = GenerateValues([]string{"password"}, Policy{Length: 10}); err == nil {
+		t.Errorf("error is expected for invalid policy")
+	}
+	// Policy is not used without keys, so values read from files are not affected by it
+	if values, err = GenerateValues(nil, Policy{}); err != nil || len(values) != 0 {
+		t.Errorf("no values and no error are expected without keys, got %v, %v", values, err)
+	}
+}
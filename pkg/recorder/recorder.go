@@ -0,0 +1,104 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recorder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Event reasons posted for credentials lifecycle steps. Reasons are stable and may be used in alerts.
+const (
+	ReasonLockAcquired      = "CredentialsLockAcquired"
+	ReasonLockExpired       = "CredentialsLockExpired"
+	ReasonOldCopySaved      = "CredentialsOldCopySaved"
+	ReasonPrepareFailed     = "CredentialsPrepareFailed"
+	ReasonChanged           = "CredentialsChanged"
+	ReasonRotationStarted   = "CredentialsRotationStarted"
+	ReasonRotationSucceeded = "CredentialsRotationSucceeded"
+	ReasonRotationFailed    = "CredentialsRotationFailed"
+	ReasonUnlocked          = "CredentialsUnlocked"
+	ReasonUnlockFailed      = "CredentialsUnlockFailed"
+	ReasonForceUnlocked     = "CredentialsForceUnlocked"
+	ReasonSecretDeleted     = "CredentialsSecretDeleted"
+	ReasonSecretRecreated   = "CredentialsSecretRecreated"
+	ReasonOldCopyDeleted    = "CredentialsOldCopyDeleted"
+	ReasonGenerated         = "CredentialsGenerated"
+	ReasonValidationFailed  = "CredentialsValidationFailed"
+)
+
+// DefaultComponent is the source component of posted Events.
+const DefaultComponent = "qubership-credential-manager"
+
+// Recorder posts Events on credentials secrets and optionally on the owner object.
+// Nil Recorder doesn't post any events.
+type Recorder struct {
+	recorder record.EventRecorder
+	owner    runtime.Object
+}
+
+// New creates Recorder. Events are duplicated on owner if it is not nil.
+func New(recorder record.EventRecorder, owner runtime.Object) *Recorder {
+	if recorder == nil {
+		return nil
+	}
+	return &Recorder{recorder: recorder, owner: owner}
+}
+
+// NewEventRecorder creates event recorder which posts Events with the clientset asynchronously.
+// Returned function stops the recorder, Events which are not posted yet are dropped, so short-lived processes should use NewSyncEventRecorder.
+func NewEventRecorder(clientSet kubernetes.Interface, component string) (record.EventRecorder, func()) {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientSet.CoreV1().Events("")})
+	eventRecorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: component})
+	return eventRecorder, broadcaster.Shutdown
+}
+
+// Normal posts Normal event on the secret.
+func (r *Recorder) Normal(secretRef types.NamespacedName, reason, messageFmt string, args ...interface{}) {
+	r.event(secretRef, corev1.EventTypeNormal, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Warning posts Warning event on the secret.
+func (r *Recorder) Warning(secretRef types.NamespacedName, reason, messageFmt string, args ...interface{}) {
+	r.event(secretRef, corev1.EventTypeWarning, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *Recorder) event(secretRef types.NamespacedName, eventType, reason, message string) {
+	if r == nil {
+		return
+	}
+	r.recorder.Event(SecretReference(secretRef), eventType, reason, message)
+	if r.owner != nil {
+		r.recorder.Event(r.owner, eventType, reason, fmt.Sprintf("secret %s: %s", secretRef, message))
+	}
+}
+
+// SecretReference returns object reference of the secret, which may be used as Event involved object.
+func SecretReference(secretRef types.NamespacedName) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       "Secret",
+		APIVersion: "v1",
+		Namespace:  secretRef.Namespace,
+		Name:       secretRef.Name,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recorder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Netcracker/qubership-credential-manager/pkg/utils"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/record/util"
+	"k8s.io/client-go/tools/reference"
+)
+
+const eventCreateTimeout = 10 * time.Second
+
+var logger = utils.GetLogger()
+
+// syncRecorder creates Events synchronously, so Events are written before short-lived process exits.
+type syncRecorder struct {
+	clientSet kubernetes.Interface
+	source    corev1.EventSource
+}
+
+// NewSyncEventRecorder creates event recorder which creates Events with the clientset before returning.
+// It should be used by short-lived processes, e.g. hooks, where asynchronously posted Events may be lost on exit.
+func NewSyncEventRecorder(clientSet kubernetes.Interface, component string) record.EventRecorder {
+	return &syncRecorder{clientSet: clientSet, source: corev1.EventSource{Component: component}}
+}
+
+func (r *syncRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventType, reason, "%s", message)
+}
+
+func (r *syncRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventType, reason, messageFmt, args...)
+}
+
+func (r *syncRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	ref, err := reference.GetReference(scheme.Scheme, object)
+	if err != nil {
+		logger.Error("Cannot get reference of the Event object", zap.Error(err))
+		return
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        util.GenerateEventName(ref.Name, now.UnixNano()),
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        fmt.Sprintf(messageFmt, args...),
+		Source:         r.source,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventType,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), eventCreateTimeout)
+	defer cancel()
+	if _, err = r.clientSet.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		logger.Error(fmt.Sprintf("Event %s of %s %s/%s wasn't created", reason, ref.Kind, ref.Namespace, ref.Name), zap.Error(err))
+	}
+}
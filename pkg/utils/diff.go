@@ -0,0 +1,191 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DiffOptions configures which parts of the secrets are compared by DiffSecrets.
+// Data is always compared.
+type DiffOptions struct {
+	// CompareStringData merges StringData over Data before comparison, the same way API server does
+	CompareStringData bool
+	// CompareLabels enables labels comparison
+	CompareLabels bool
+	// Annotations is the list of annotation names to compare
+	Annotations []string
+}
+
+// SecretDiff contains names of the keys which differ between two secrets. Values are never included.
+type SecretDiff struct {
+	AddedKeys   []string
+	RemovedKeys []string
+	ChangedKeys []string
+
+	ChangedLabels      []string
+	ChangedAnnotations []string
+}
+
+// HasChanges checks if any difference was found.
+func (d SecretDiff) HasChanges() bool {
+	return len(d.AddedKeys) > 0 || len(d.RemovedKeys) > 0 || len(d.ChangedKeys) > 0 ||
+		len(d.ChangedLabels) > 0 || len(d.ChangedAnnotations) > 0
+}
+
+// DiffSecrets compares oldSecret and newSecret and returns found differences.
+func DiffSecrets(oldSecret, newSecret *corev1.Secret, opts DiffOptions) SecretDiff {
+	diff := SecretDiff{}
+	diff.AddedKeys, diff.RemovedKeys, diff.ChangedKeys = diffData(
+		secretData(oldSecret, opts.CompareStringData), secretData(newSecret, opts.CompareStringData))
+	if opts.CompareLabels {
+		diff.ChangedLabels = diffStrings(oldSecret.Labels, newSecret.Labels, nil)
+	}
+	if len(opts.Annotations) > 0 {
+		diff.ChangedAnnotations = diffStrings(oldSecret.Annotations, newSecret.Annotations, opts.Annotations)
+	}
+	return diff
+}
+
+func secretData(secret *corev1.Secret, withStringData bool) map[string][]byte {
+	if !withStringData || len(secret.StringData) == 0 {
+		return secret.Data
+	}
+	data := make(map[string][]byte, len(secret.Data)+len(secret.StringData))
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+	for key, value := range secret.StringData {
+		data[key] = []byte(value)
+	}
+	return data
+}
+
+func diffData(oldData, newData map[string][]byte) (added, removed, changed []string) {
+	for key, newValue := range newData {
+		oldValue, found := oldData[key]
+		if !found {
+			added = append(added, key)
+		} else if string(oldValue) != string(newValue) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldData {
+		if _, found := newData[key]; !found {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return
+}
+
+// diffStrings returns keys with different values, only selected keys are compared if keys are provided.
+func diffStrings(oldMap, newMap map[string]string, keys []string) []string {
+	var changed []string
+	if keys == nil {
+		for key := range oldMap {
+			keys = append(keys, key)
+		}
+		for key := range newMap {
+			if _, found := oldMap[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+	}
+	for _, key := range keys {
+		oldValue, oldFound := oldMap[key]
+		newValue, newFound := newMap[key]
+		if oldFound != newFound || oldValue != newValue {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// MergeKeys returns sorted union of the key lists.
+func MergeKeys(keys, newKeys []string) []string {
+	merged := slices.Clone(keys)
+	for _, key := range newKeys {
+		if !slices.Contains(merged, key) {
+			merged = append(merged, key)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// MergeDiffs combines two sequential diffs of the same secret into diff between the first old and the second new versions.
+// Keys removed and added back are reported as changed, keys added and removed back are dropped.
+func MergeDiffs(first, second SecretDiff) SecretDiff {
+	const (
+		added = iota + 1
+		removed
+		changed
+	)
+	changes := make(map[string]int)
+	for _, key := range first.AddedKeys {
+		changes[key] = added
+	}
+	for _, key := range first.RemovedKeys {
+		changes[key] = removed
+	}
+	for _, key := range first.ChangedKeys {
+		changes[key] = changed
+	}
+	for _, key := range second.AddedKeys {
+		if changes[key] == removed {
+			changes[key] = changed
+		} else {
+			changes[key] = added
+		}
+	}
+	for _, key := range second.RemovedKeys {
+		if changes[key] == added {
+			delete(changes, key)
+		} else {
+			changes[key] = removed
+		}
+	}
+	for _, key := range second.ChangedKeys {
+		if changes[key] != added {
+			changes[key] = changed
+		}
+	}
+
+	merged := SecretDiff{
+		ChangedLabels:      MergeKeys(first.ChangedLabels, second.ChangedLabels),
+		ChangedAnnotations: MergeKeys(first.ChangedAnnotations, second.ChangedAnnotations),
+	}
+	for key, change := range changes {
+		switch change {
+		case added:
+			merged.AddedKeys = append(merged.AddedKeys, key)
+		case removed:
+			merged.RemovedKeys = append(merged.RemovedKeys, key)
+		case changed:
+			merged.ChangedKeys = append(merged.ChangedKeys, key)
+		}
+	}
+	sort.Strings(merged.AddedKeys)
+	sort.Strings(merged.RemovedKeys)
+	sort.Strings(merged.ChangedKeys)
+	return merged
+}
@@ -0,0 +1,159 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffSecrets(t *testing.T) {
+	oldSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "db", "tier": "backend"},
+			Annotations: map[string]string{"version": "1", "ignored": "1"},
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("old"),
+			"host":     []byte("db"),
+		},
+	}
+	tests := []struct {
+		name      string
+		newSecret *corev1.Secret
+		opts      DiffOptions
+		expected  SecretDiff
+	}{
+		{
+			name:      "no changes",
+			newSecret: oldSecret.DeepCopy(),
+		},
+		{
+			name: "added, removed and changed keys",
+			newSecret: &corev1.Secret{Data: map[string][]byte{
+				"username": []byte("admin"),
+				"password": []byte("new"),
+				"port":     []byte("5432"),
+				"database": []byte("app"),
+			}},
+			expected: SecretDiff{
+				AddedKeys:   []string{"database", "port"},
+				RemovedKeys: []string{"host"},
+				ChangedKeys: []string{"password"},
+			},
+		},
+		{
+			name: "string data is merged over data",
+			newSecret: &corev1.Secret{
+				Data:       oldSecret.Data,
+				StringData: map[string]string{"password": "new", "port": "5432"},
+			},
+			opts: DiffOptions{CompareStringData: true},
+			expected: SecretDiff{
+				AddedKeys:   []string{"port"},
+				ChangedKeys: []string{"password"},
+			},
+		},
+		{
+			name: "string data is ignored by default",
+			newSecret: &corev1.Secret{
+				Data:       oldSecret.Data,
+				StringData: map[string]string{"password": "new"},
+			},
+		},
+		{
+			name: "labels and selected annotations",
+			newSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "db", "team": "dba"},
+					Annotations: map[string]string{"version": "2", "ignored": "2"},
+				},
+				Data: oldSecret.Data,
+			},
+			opts: DiffOptions{CompareLabels: true, Annotations: []string{"version"}},
+			expected: SecretDiff{
+				ChangedLabels:      []string{"team", "tier"},
+				ChangedAnnotations: []string{"version"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diff := DiffSecrets(oldSecret, test.newSecret, test.opts)
+			if !reflect.DeepEqual(diff, test.expected) {
+				t.Errorf("unexpected diff %+v, expected %+v", diff, test.expected)
+			}
+			if diff.HasChanges() != !reflect.DeepEqual(test.expected, SecretDiff{}) {
+				t.Errorf("HasChanges returned %t for diff %+v", diff.HasChanges(), diff)
+			}
+		})
+	}
+}
+
+func TestMergeDiffs(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    SecretDiff
+		second   SecretDiff
+		expected SecretDiff
+	}{
+		{
+			name:     "independent keys",
+			first:    SecretDiff{AddedKeys: []string{"port"}, ChangedLabels: []string{"app"}},
+			second:   SecretDiff{RemovedKeys: []string{"host"}, ChangedKeys: []string{"password"}, ChangedLabels: []string{"team"}},
+			expected: SecretDiff{AddedKeys: []string{"port"}, RemovedKeys: []string{"host"}, ChangedKeys: []string{"password"}, ChangedLabels: []string{"app", "team"}},
+		},
+		{
+			name:     "removed and added back",
+			first:    SecretDiff{RemovedKeys: []string{"password"}},
+			second:   SecretDiff{AddedKeys: []string{"password"}},
+			expected: SecretDiff{ChangedKeys: []string{"password"}},
+		},
+		{
+			name:   "added and removed back",
+			first:  SecretDiff{AddedKeys: []string{"password"}},
+			second: SecretDiff{RemovedKeys: []string{"password"}},
+		},
+		{
+			name:     "added and changed",
+			first:    SecretDiff{AddedKeys: []string{"password"}},
+			second:   SecretDiff{ChangedKeys: []string{"password"}},
+			expected: SecretDiff{AddedKeys: []string{"password"}},
+		},
+		{
+			name:     "changed and removed",
+			first:    SecretDiff{ChangedKeys: []string{"password"}},
+			second:   SecretDiff{RemovedKeys: []string{"password"}},
+			expected: SecretDiff{RemovedKeys: []string{"password"}},
+		},
+		{
+			name:     "changed twice",
+			first:    SecretDiff{ChangedKeys: []string{"password", "username"}},
+			second:   SecretDiff{ChangedKeys: []string{"password"}},
+			expected: SecretDiff{ChangedKeys: []string{"password", "username"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if merged := MergeDiffs(test.first, test.second); !reflect.DeepEqual(merged, test.expected) {
+				t.Errorf("unexpected merged diff %+v, expected %+v", merged, test.expected)
+			}
+		})
+	}
+}
@@ -16,12 +16,17 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
@@ -32,7 +37,9 @@ const nsPath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 
 var (
 	logger    *zap.Logger
+	logOutput = &switchableWriter{writer: os.Stdout}
 	k8sClient client.Client
+	clientSet kubernetes.Interface
 )
 
 func GetLogger() *zap.Logger {
@@ -51,7 +58,7 @@ func createLogger() *zap.Logger {
 
 	newLogger := zap.New(zapcore.NewCore(
 		zapcore.NewJSONEncoder(encoderCfg),
-		zapcore.Lock(os.Stdout),
+		zapcore.AddSync(logOutput),
 		atom,
 	))
 	defer func() {
@@ -60,6 +67,30 @@ func createLogger() *zap.Logger {
 	return newLogger
 }
 
+// SetLogOutput redirects output of the logger, e.g. to stderr in command line tools. By default stdout is used.
+// Loggers obtained before the call are redirected too.
+func SetLogOutput(writer io.Writer) {
+	logOutput.set(writer)
+}
+
+// switchableWriter is logger output which may be replaced after logger creation.
+type switchableWriter struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func (w *switchableWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.writer.Write(p)
+}
+
+func (w *switchableWriter) set(writer io.Writer) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.writer = writer
+}
+
 func GetK8SClient() client.Client {
 	if k8sClient == nil {
 		k8sClient = createClient()
@@ -69,28 +100,68 @@ func GetK8SClient() client.Client {
 }
 
 func createClient() client.Client {
-	clientConfig, err := config.GetConfig()
+	client, err := NewK8SClient()
 	if err != nil {
 		panic(err.Error())
 	}
-	client, err := client.New(clientConfig, client.Options{})
+	return client
+}
+
+// NewK8SClient creates client from the environment config, error is returned if config can't be loaded.
+func NewK8SClient() (client.Client, error) {
+	clientConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(clientConfig, client.Options{})
+}
+
+func GetClientSet() kubernetes.Interface {
+	if clientSet == nil {
+		clientSet = createClientSet()
+	}
+
+	return clientSet
+}
+
+func createClientSet() kubernetes.Interface {
+	clientSet, err := NewClientSet()
 	if err != nil {
 		panic(err.Error())
 	}
-	return client
+	return clientSet
+}
+
+// NewClientSet creates clientset from the environment config, error is returned if config can't be loaded.
+func NewClientSet() (kubernetes.Interface, error) {
+	clientConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientConfig.Timeout = 60 * time.Second
+	return kubernetes.NewForConfig(clientConfig)
 }
 
 func GetNamespace() string {
+	namespace, err := LookupNamespace()
+	if err != nil {
+		GetLogger().Error("namespace can't be extracted", zap.Error(err))
+		panic(err)
+	}
+	return namespace
+}
+
+// LookupNamespace returns namespace of the service account or NAMESPACE environment variable.
+func LookupNamespace() (string, error) {
 	namespace, err := ReadFromFile(nsPath)
 	if err != nil {
 		//try read namespace from env var
 		namespace = os.Getenv("NAMESPACE")
 		if namespace == "" {
-			logger.Error("namespace can't be extracted", zap.Error(err))
-			panic(err)
+			return "", fmt.Errorf("namespace can't be extracted: %w", err)
 		}
 	}
-	return namespace
+	return namespace, nil
 }
 
 func ReadFromFile(filePath string) (string, error) {
@@ -109,26 +180,66 @@ func GetEnv(key string, def string) string {
 	return v
 }
 
-func AreFieldsChanged(oldSecret, newSecret *corev1.Secret) bool {
-	isChanged := false
-	for fieldName := range newSecret.Data {
-		if string(oldSecret.Data[fieldName]) != string(newSecret.Data[fieldName]) {
-			isChanged = true
-		}
+// GetEnvDuration returns duration from the environment variable, def is returned if variable is empty or invalid.
+func GetEnvDuration(key string, def time.Duration) time.Duration {
+	duration, err := time.ParseDuration(GetEnv(key, def.String()))
+	if err != nil {
+		GetLogger().Error(fmt.Sprintf("cannot parse %s, %s is used", key, def), zap.Error(err))
+		return def
 	}
-	return isChanged
+	return duration
+}
+
+// AreFieldsChanged checks if any data key was added, removed or changed.
+func AreFieldsChanged(oldSecret, newSecret *corev1.Secret) bool {
+	return DiffSecrets(oldSecret, newSecret, DiffOptions{}).HasChanges()
 }
 
 func GetOldSecretName(secretName string) string {
 	return fmt.Sprintf("%s-old", secretName)
 }
 
+// GetOldSecretRef returns reference to the "-old" copy of the secret, the copy is located in the same namespace.
+func GetOldSecretRef(secretRef types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{Namespace: secretRef.Namespace, Name: GetOldSecretName(secretRef.Name)}
+}
+
+// GetSecretRefs converts secret names to references. Names may be provided in "namespace/name" form,
+// otherwise defaultNamespace is used.
+func GetSecretRefs(secretNames []string, defaultNamespace string) []types.NamespacedName {
+	secretRefs := make([]types.NamespacedName, 0, len(secretNames))
+	for _, secretName := range secretNames {
+		secretRefs = append(secretRefs, GetSecretRef(secretName, defaultNamespace))
+	}
+	return secretRefs
+}
+
+// GetSecretRef converts secret name in "namespace/name" or "name" form to reference.
+func GetSecretRef(secretName string, defaultNamespace string) types.NamespacedName {
+	secretName = strings.TrimSpace(secretName)
+	if namespace, name, found := strings.Cut(secretName, "/"); found {
+		return types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return types.NamespacedName{Namespace: defaultNamespace, Name: secretName}
+}
+
 func GetSecretNames() []string {
 	secretNamesStr := os.Getenv("SECRET_NAMES")
 	secretNames := strings.Split(secretNamesStr, ",")
 	return secretNames
 }
 
+// SplitList splits comma separated list, empty items are skipped.
+func SplitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func GetHookName() string {
 	return GetEnv("HOOK_NAME", "credentials-saver")
 }